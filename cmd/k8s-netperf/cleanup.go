@@ -0,0 +1,93 @@
+package main
+
+import (
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/k8s"
+	log "github.com/cloud-bulldozer/k8s-netperf/pkg/logging"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+var cleanupAll bool
+
+// sriovGroupVersion is the API served by the SR-IOV network operator.
+const sriovGroupVersion = "sriovnetwork.openshift.io/v1"
+
+var cleanupCmd = &cobra.Command{
+	Use:   "cleanup",
+	Short: "Remove the namespace, CUDN, SR-IOV and localnet resources left behind by k8s-netperf",
+	Long: `Remove the namespace, CUDN, SR-IOV and localnet resources left behind by k8s-netperf,
+e.g. after a run was interrupted. Resources which do not exist are skipped.
+
+The namespace is selected with --namespace, or with --uuid for the default namespace
+of a run. The cluster-scoped CUDN and SR-IOV resources are only removed when they
+were created by the same run. --all removes every namespace, CUDN and SR-IOV resource
+created by k8s-netperf, including those of runs still in progress on the cluster;
+removing the SR-IOV policy may reboot the nodes it configured.`,
+	Args: cobra.NoArgs,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		if !cleanupAll && namespace == "" && id == "" {
+			log.Fatal("Pass the namespace to remove with --namespace, the UUID of its run with --uuid, or --all")
+		}
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		kconfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+			clientcmd.NewDefaultClientConfigLoadingRules(),
+			&clientcmd.ConfigOverrides{})
+		rconfig, err := kconfig.ClientConfig()
+		if err != nil {
+			log.Fatal(err)
+		}
+		client, err := kubernetes.NewForConfig(rconfig)
+		if err != nil {
+			log.Fatal(err)
+		}
+		dynClient, err := dynamic.NewForConfig(rconfig)
+		if err != nil {
+			log.Fatal(err)
+		}
+		// owner is the UUID of the run whose cluster-scoped resources are removed, any run with --all.
+		var namespaces []string
+		owner := ""
+		switch {
+		case cleanupAll:
+			namespaces, err = k8s.ManagedNamespaces(client)
+			if err != nil {
+				log.Fatal(err)
+			}
+		case namespace != "":
+			namespaces = []string{namespace}
+			owner, err = k8s.NamespaceOwner(client, namespace)
+			if err != nil {
+				log.Fatal(err)
+			}
+		default:
+			namespaces = []string{k8s.NamespaceForRun(id)}
+			owner = id
+		}
+		if !cleanupAll && owner == "" {
+			log.Infof("The run of namespace %s is unknown, skipping the CUDN and SR-IOV resources, remove them with --all", namespace)
+		} else {
+			k8s.CleanupCUdns(dynClient, owner)
+			if _, err := client.Discovery().ServerResourcesForGroupVersion(sriovGroupVersion); err == nil {
+				k8s.CleanupSriov(dynClient, owner)
+			} else {
+				log.Debugf("SR-IOV operator API not available, skipping: %v", err)
+			}
+		}
+		for _, ns := range namespaces {
+			if err := k8s.DestroyNamespaceByName(client, ns); err != nil {
+				log.Fatal(err)
+			}
+		}
+		log.Info("✅ Cleanup complete")
+	},
+}
+
+func init() {
+	cleanupCmd.Flags().StringVar(&namespace, "namespace", "", "Namespace to remove")
+	cleanupCmd.Flags().StringVar(&id, "uuid", "", "UUID of the run whose default namespace to remove")
+	cleanupCmd.Flags().BoolVar(&cleanupAll, "all", false, "Remove all the namespaces created by k8s-netperf (default false)")
+	cleanupCmd.MarkFlagsMutuallyExclusive("namespace", "uuid", "all")
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/archive"
+	result "github.com/cloud-bulldozer/k8s-netperf/pkg/results"
+	"github.com/spf13/cobra"
+)
+
+var (
+	compareTest          string
+	compareAlpha         float64
+	compareIgnoreNetwork bool
+)
+
+var compareCmd = &cobra.Command{
+	Use:   "compare <base.json> <new.json>",
+	Short: "Compare two k8s-netperf JSON results and report statistically significant differences",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if compareAlpha <= 0 || compareAlpha >= 1 {
+			return fmt.Errorf("--alpha must be between 0 and 1")
+		}
+		base, err := archive.ReadJSONResult(args[0])
+		if err != nil {
+			return fmt.Errorf("unable to load base result: %v", err)
+		}
+		cur, err := archive.ReadJSONResult(args[1])
+		if err != nil {
+			return fmt.Errorf("unable to load new result: %v", err)
+		}
+		cmp, unmatched, err := result.Compare(base, cur, compareTest, compareIgnoreNetwork)
+		if err != nil {
+			return err
+		}
+		if len(cmp) == 0 {
+			return fmt.Errorf("no matching tests between %s and %s", args[0], args[1])
+		}
+		fmt.Printf("Base: %s (%s)\nNew:  %s (%s)\n", args[0], base.Version, args[1], cur.Version)
+		result.ShowComparison(cmp, unmatched, compareAlpha)
+		return nil
+	},
+}
+
+func init() {
+	compareCmd.Flags().StringVar(&compareTest, "test", result.WelchTTest, "Statistical test used to compare the samples, welch (Welch's t-test) or utest (Mann-Whitney U test)")
+	compareCmd.Flags().Float64Var(&compareAlpha, "alpha", 0.05, "Significance level of the comparison (default 0.05)")
+	compareCmd.Flags().BoolVar(&compareIgnoreNetwork, "ignore-network", false, "Match the tests regardless of their network (UDN, CUDN, bridge, SR-IOV, MACVLAN, localnet) and runtime class, e.g. to compare a UDN run to a default network run (default false)")
+}
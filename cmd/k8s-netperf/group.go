@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/config"
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/drivers"
+	log "github.com/cloud-bulldozer/k8s-netperf/pkg/logging"
+	result "github.com/cloud-bulldozer/k8s-netperf/pkg/results"
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/sample"
+	apiv1 "k8s.io/api/core/v1"
+)
+
+// endpoint is one of the concurrent client to server tests of an incast, fan-out or pairs test.
+type endpoint struct {
+	client   apiv1.PodList
+	serverIP string
+	// node of the client of an incast, of the server of a fan-out, or both nodes of a pair
+	node string
+	// portOffset keeps the data ports of the concurrent tests apart
+	portOffset int
+}
+
+// checkGroupDriver returns why the driver can not run the topology of the test, nil when it can.
+func checkGroupDriver(driverName string, nc config.Config) error {
+	switch {
+	case driverName == "netperf":
+		return nil
+	case nc.Pairs > 0 && (driverName == "iperf3" || driverName == "uperf"):
+		// Each pair has its own server.
+		return nil
+	case nc.Fanout > 0 && driverName == "iperf3":
+		return nil
+	case nc.Incast > 0 && driverName == "iperf3":
+		return fmt.Errorf("the iperf3 server serves one client at a time, incast tests need netperf")
+	}
+	return fmt.Errorf("%s does not run %s tests, use netperf", driverName, nc.Topology())
+}
+
+// groupEndpoints returns the endpoints of an incast, from each incast client to the server,
+// of a fan-out, from the client to each fan-out server, or of the pairs, from each client
+// to the server of its pair.
+func groupEndpoints(nc config.Config, s config.PerfScenarios, client apiv1.PodList, serverIP string) ([]endpoint, error) {
+	var eps []endpoint
+	switch {
+	case nc.Incast > 0:
+		if len(s.IncastClients.Items) < nc.Incast {
+			return nil, fmt.Errorf("%d incast clients are running, %d are needed", len(s.IncastClients.Items), nc.Incast)
+		}
+		for i, pod := range s.IncastClients.Items[:nc.Incast] {
+			eps = append(eps, endpoint{
+				client:     apiv1.PodList{Items: []apiv1.Pod{pod}},
+				serverIP:   serverIP,
+				node:       pod.Spec.NodeName,
+				portOffset: i * nc.Parallelism,
+			})
+		}
+	case nc.Fanout > 0:
+		if len(s.FanoutServers.Items) < nc.Fanout {
+			return nil, fmt.Errorf("%d fan-out servers are running, %d are needed", len(s.FanoutServers.Items), nc.Fanout)
+		}
+		for i, pod := range s.FanoutServers.Items[:nc.Fanout] {
+			eps = append(eps, endpoint{
+				client:     client,
+				serverIP:   pod.Status.PodIP,
+				node:       pod.Spec.NodeName,
+				portOffset: i * nc.Parallelism,
+			})
+		}
+	case nc.Pairs > 0:
+		clients, servers := s.PairClients, s.PairServers
+		if nc.Spread {
+			clients, servers = s.SpreadPairClients, s.SpreadPairServers
+		}
+		if len(clients.Items) < nc.Pairs || len(servers.Items) < nc.Pairs {
+			return nil, fmt.Errorf("%d clients and %d servers are running, %d pairs are needed", len(clients.Items), len(servers.Items), nc.Pairs)
+		}
+		for i := 0; i < nc.Pairs; i++ {
+			c, srv := clients.Items[i], servers.Items[i]
+			eps = append(eps, endpoint{
+				client:   apiv1.PodList{Items: []apiv1.Pod{c}},
+				serverIP: srv.Status.PodIP,
+				node:     c.Spec.NodeName + "->" + srv.Spec.NodeName,
+			})
+		}
+	}
+	return eps, nil
+}
+
+// runGroup runs the test on all the endpoints at once. The tests are released together
+// once they are all ready to start, so that they overlap for the whole duration.
+// returns the aggregated sample and the throughput of each endpoint.
+func runGroup(driver drivers.Driver, s *config.PerfScenarios, nc config.Config, eps []endpoint) (sample.Sample, []float64, error) {
+	samples := make([]sample.Sample, len(eps))
+	errs := make([]error, len(eps))
+	start := make(chan struct{})
+	var ready, done sync.WaitGroup
+	for i, ep := range eps {
+		ready.Add(1)
+		done.Add(1)
+		go func() {
+			defer done.Done()
+			ec := nc
+			ec.PortOffset = ep.portOffset
+			ready.Done()
+			<-start
+			out, err := driver.Run(s.ClientSet, s.RestConfig, ec, ep.client, ep.serverIP, s, false)
+			if err == nil {
+				samples[i], err = driver.ParseResults(&out, ec)
+			}
+			errs[i] = err
+		}()
+	}
+	ready.Wait()
+	log.Debugf("🚀 Starting %d concurrent %s tests", len(eps), nc.Topology())
+	close(start)
+	done.Wait()
+	per := make([]float64, len(eps))
+	for i, err := range errs {
+		if err != nil {
+			return sample.Sample{}, nil, fmt.Errorf("%s endpoint %d on node %s: %v", nc.Topology(), i+1, eps[i].node, err)
+		}
+		per[i] = samples[i].Throughput
+	}
+	return result.Aggregate(samples), per, nil
+}
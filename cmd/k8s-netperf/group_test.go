@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/config"
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/sample"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// barrierDriver reports the throughput of the port offset of the test, and fails the
+// test unless all the endpoints are running at the same time.
+type barrierDriver struct {
+	mu      sync.Mutex
+	running int
+	want    int
+	all     chan struct{}
+}
+
+func (d *barrierDriver) IsTestSupported() bool { return true }
+
+func (d *barrierDriver) Run(_ *kubernetes.Clientset, _ rest.Config, nc config.Config, _ apiv1.PodList, serverIP string, _ *config.PerfScenarios, _ bool) (bytes.Buffer, error) {
+	d.mu.Lock()
+	d.running++
+	if d.running == d.want {
+		close(d.all)
+	}
+	d.mu.Unlock()
+	select {
+	case <-d.all:
+	case <-time.After(5 * time.Second):
+		return bytes.Buffer{}, fmt.Errorf("not all the endpoints started")
+	}
+	if serverIP == "fail" {
+		return bytes.Buffer{}, fmt.Errorf("connection refused")
+	}
+	return *bytes.NewBufferString(fmt.Sprint(1000 + nc.PortOffset)), nil
+}
+
+func (d *barrierDriver) ParseResults(stdout *bytes.Buffer, _ config.Config) (sample.Sample, error) {
+	var tput float64
+	_, err := fmt.Sscan(stdout.String(), &tput)
+	return sample.Sample{Throughput: tput, Latency99ptile: tput / 10}, err
+}
+
+func testPod(name, node, ip string) apiv1.Pod {
+	return apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       apiv1.PodSpec{NodeName: node},
+		Status:     apiv1.PodStatus{PodIP: ip},
+	}
+}
+
+func TestGroupEndpoints(t *testing.T) {
+	client := apiv1.PodList{Items: []apiv1.Pod{testPod("client", "n0", "10.0.0.1")}}
+	s := config.PerfScenarios{
+		IncastClients: apiv1.PodList{Items: []apiv1.Pod{testPod("i1", "n1", "10.0.1.1"), testPod("i2", "n2", "10.0.2.1"), testPod("i3", "n3", "10.0.3.1")}},
+		FanoutServers: apiv1.PodList{Items: []apiv1.Pod{testPod("f1", "n4", "10.0.4.1"), testPod("f2", "n5", "10.0.5.1")}},
+	}
+	eps, err := groupEndpoints(config.Config{Incast: 2, Parallelism: 4}, s, client, "10.0.9.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(eps) != 2 || eps[1].node != "n2" || eps[1].serverIP != "10.0.9.1" || eps[1].client.Items[0].Name != "i2" || eps[1].portOffset != 4 {
+		t.Fatalf("unexpected incast endpoints %+v", eps)
+	}
+	eps, err = groupEndpoints(config.Config{Fanout: 2, Parallelism: 1}, s, client, "10.0.9.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(eps) != 2 || eps[1].node != "n5" || eps[1].serverIP != "10.0.5.1" || eps[1].client.Items[0].Name != "client" || eps[1].portOffset != 1 {
+		t.Fatalf("unexpected fan-out endpoints %+v", eps)
+	}
+	if _, err := groupEndpoints(config.Config{Fanout: 3, Parallelism: 1}, s, client, "10.0.9.1"); err == nil {
+		t.Fatal("expected an error with more fan-out servers than running")
+	}
+	s.SpreadPairClients = apiv1.PodList{Items: []apiv1.Pod{testPod("c1", "n1", "10.0.1.2"), testPod("c2", "n2", "10.0.2.2")}}
+	s.SpreadPairServers = apiv1.PodList{Items: []apiv1.Pod{testPod("s1", "n3", "10.0.3.2"), testPod("s2", "n4", "10.0.4.2")}}
+	eps, err = groupEndpoints(config.Config{Pairs: 2, Spread: true, Parallelism: 1}, s, client, "10.0.9.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(eps) != 2 || eps[1].node != "n2->n4" || eps[1].serverIP != "10.0.4.2" || eps[1].client.Items[0].Name != "c2" || eps[1].portOffset != 0 {
+		t.Fatalf("unexpected pairs endpoints %+v", eps)
+	}
+	if _, err := groupEndpoints(config.Config{Pairs: 2, Parallelism: 1}, s, client, "10.0.9.1"); err == nil {
+		t.Fatal("expected an error without pairs on the client and server nodes")
+	}
+}
+
+func TestRunGroup(t *testing.T) {
+	eps := []endpoint{
+		{serverIP: "10.0.0.1", node: "n1", portOffset: 0},
+		{serverIP: "10.0.0.1", node: "n2", portOffset: 1},
+		{serverIP: "10.0.0.1", node: "n3", portOffset: 2},
+	}
+	d := &barrierDriver{want: len(eps), all: make(chan struct{})}
+	nr, per, err := runGroup(d, &config.PerfScenarios{}, config.Config{Incast: 3}, eps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nr.Throughput != 3003 || nr.Latency99ptile != 100.2 {
+		t.Fatalf("runGroup aggregated %+v, want a throughput of 3003 and a 99%%tile latency of 100.2", nr)
+	}
+	if len(per) != 3 || per[0] != 1000 || per[2] != 1002 {
+		t.Fatalf("runGroup returned the endpoint throughputs %v", per)
+	}
+	eps[1].serverIP = "fail"
+	d = &barrierDriver{want: len(eps), all: make(chan struct{})}
+	if _, _, err := runGroup(d, &config.PerfScenarios{}, config.Config{Incast: 3}, eps); err == nil || !strings.Contains(err.Error(), "endpoint 2 on node n2") {
+		t.Fatalf("expected the failure of endpoint 2, got %v", err)
+	}
+}
+
+func TestCheckGroupDriver(t *testing.T) {
+	testCases := []struct {
+		driver string
+		cfg    config.Config
+		ok     bool
+	}{
+		{driver: "netperf", cfg: config.Config{Incast: 2}, ok: true},
+		{driver: "iperf3", cfg: config.Config{Incast: 2}},
+		{driver: "iperf3", cfg: config.Config{Fanout: 2}, ok: true},
+		{driver: "uperf", cfg: config.Config{Fanout: 2}},
+		{driver: "uperf", cfg: config.Config{Pairs: 2}, ok: true},
+		{driver: "iperf3", cfg: config.Config{Pairs: 2, Spread: true}, ok: true},
+		{driver: "ib_write_bw", cfg: config.Config{Pairs: 2}},
+	}
+	for _, tc := range testCases {
+		if err := checkGroupDriver(tc.driver, tc.cfg); (err == nil) != tc.ok {
+			t.Fatalf("checkGroupDriver(%s, %s) returned %v", tc.driver, tc.cfg.Topology(), err)
+		}
+	}
+}
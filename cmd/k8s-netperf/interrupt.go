@@ -0,0 +1,63 @@
+package main
+
+import (
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/k8s"
+	log "github.com/cloud-bulldozer/k8s-netperf/pkg/logging"
+)
+
+// handleInterrupts runs teardown and exits when the run receives SIGINT or SIGTERM,
+// e.g. on Ctrl+C or a CI timeout. A second signal exits without waiting for teardown.
+func handleInterrupts(teardown func()) {
+	sigs := make(chan os.Signal, 2)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		sig := <-sigs
+		log.Warnf("🛑 Received %s, stopping the run. Send it again to exit immediately", sig)
+		go func() {
+			<-sigs
+			log.Error("😥 Exiting without cleanup, remove the leftover resources with k8s-netperf cleanup")
+			os.Exit(1)
+		}()
+		teardown()
+		os.Exit(130)
+	}()
+}
+
+// resumeCommand returns the command line resuming the run with the given UUID, args
+// with --resume set to the UUID.
+func resumeCommand(args []string, uid string) string {
+	var cmd []string
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--resume":
+			i++
+			continue
+		case strings.HasPrefix(args[i], "--resume="):
+			continue
+		}
+		cmd = append(cmd, shellQuote(args[i]))
+	}
+	return strings.Join(append(cmd, "--resume", uid), " ")
+}
+
+// cleanupCommand returns the command line removing the resources of the run with the
+// given UUID, deployed in the namespace ns.
+func cleanupCommand(uid, ns string) string {
+	if ns != k8s.NamespaceForRun(uid) {
+		return "k8s-netperf cleanup --namespace " + ns
+	}
+	return "k8s-netperf cleanup --uuid " + uid
+}
+
+// shellQuote quotes s for a POSIX shell when needed.
+func shellQuote(s string) string {
+	if s != "" && !strings.ContainsAny(s, " \t\n'\"$`\\*?;&|<>()[]{}~#!") {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,28 @@
+package main
+
+import "testing"
+
+func TestResumeCommand(t *testing.T) {
+	testCases := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"k8s-netperf", "--config", "netperf.yml", "--all"}, "k8s-netperf --config netperf.yml --all --resume 2f1c6a3e"},
+		{[]string{"k8s-netperf", "--resume", "2f1c6a3e", "--all"}, "k8s-netperf --all --resume 2f1c6a3e"},
+		{[]string{"k8s-netperf", "--resume=2f1c6a3e", "--client-node-selector", "pool in (a,b)"}, "k8s-netperf --client-node-selector 'pool in (a,b)' --resume 2f1c6a3e"},
+	}
+	for _, tc := range testCases {
+		if got := resumeCommand(tc.args, "2f1c6a3e"); got != tc.want {
+			t.Fatalf("resumeCommand(%q) = %q, want %q", tc.args, got, tc.want)
+		}
+	}
+}
+
+func TestCleanupCommand(t *testing.T) {
+	if got, want := cleanupCommand("2f1c6a3e-9b1d", "netperf-2f1c6a3e"), "k8s-netperf cleanup --uuid 2f1c6a3e-9b1d"; got != want {
+		t.Fatalf("cleanupCommand() = %q, want %q", got, want)
+	}
+	if got, want := cleanupCommand("2f1c6a3e-9b1d", "perf"), "k8s-netperf cleanup --namespace perf"; got != want {
+		t.Fatalf("cleanupCommand() = %q, want %q", got, want)
+	}
+}
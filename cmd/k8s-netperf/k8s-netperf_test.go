@@ -1,10 +1,19 @@
 package main
 
 import (
+	"bytes"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	ocpmetadata "github.com/cloud-bulldozer/go-commons/v2/ocp-metadata"
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/config"
 	"github.com/cloud-bulldozer/k8s-netperf/pkg/metrics"
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/sample"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 )
 
 func TestApplyClusterDistributionSetsPrometheusFlags(t *testing.T) {
@@ -125,3 +134,122 @@ func TestShouldDiscoverPrometheus(t *testing.T) {
 		})
 	}
 }
+
+func TestPlanTests(t *testing.T) {
+	cfg := []config.Config{
+		{Profile: "TCP_STREAM", Duration: 10, Samples: 3, MessageSize: 1024, Parallelism: 1},
+		{Profile: "TCP_RR", Duration: 10, Samples: 3, MessageSize: 1024, Parallelism: 1, Service: true},
+	}
+	testCases := []struct {
+		name        string
+		cfg         []config.Config
+		drivers     []string
+		hostNetwork bool
+		hostNetOnly bool
+		pod         bool
+		vm          bool
+		mixed       bool
+		sandbox     string
+		want        int
+		supported   int
+	}{
+		{name: "pod network", drivers: []string{"netperf"}, pod: true, want: 2, supported: 2},
+		{name: "all", drivers: []string{"netperf"}, hostNetwork: true, pod: true, want: 3, supported: 3},
+		{name: "host network only", drivers: []string{"netperf"}, hostNetwork: true, hostNetOnly: true, pod: true, vm: true, want: 1, supported: 1},
+		{name: "pod and vm", drivers: []string{"netperf", "iperf3"}, pod: true, vm: true, want: 8, supported: 6},
+		{name: "sandbox", drivers: []string{"netperf"}, hostNetwork: true, pod: true, sandbox: "kata", want: 4, supported: 4},
+		{name: "mixed", drivers: []string{"netperf", "iperf3"}, pod: true, vm: true, mixed: true, want: 16, supported: 12},
+		{name: "incast", cfg: append(cfg, config.Config{Profile: "TCP_STREAM", Duration: 10, Samples: 3, MessageSize: 1024, Parallelism: 1, Incast: 4}), drivers: []string{"netperf", "iperf3"}, hostNetwork: true, pod: true, want: 10, supported: 6},
+		{name: "fan-out", cfg: append(cfg, config.Config{Profile: "TCP_STREAM", Duration: 10, Samples: 3, MessageSize: 1024, Parallelism: 1, Fanout: 3}), drivers: []string{"netperf", "iperf3", "uperf"}, pod: true, want: 9, supported: 7},
+		{name: "migrate", cfg: []config.Config{{Profile: "TCP_STREAM", Duration: 30, Samples: 1, MessageSize: 1024, Parallelism: 1, Migrate: config.MigrateServer, MigrateAfter: 10}}, drivers: []string{"netperf", "iperf3"}, pod: true, vm: true, want: 4, supported: 1},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.cfg == nil {
+				tc.cfg = cfg
+			}
+			plan := planTests(tc.cfg, tc.drivers, tc.hostNetwork, tc.hostNetOnly, tc.pod, tc.vm, tc.mixed, tc.sandbox)
+			if len(plan) != tc.want {
+				t.Fatalf("planTests returned %d tests, want %d", len(plan), tc.want)
+			}
+			supported := 0
+			for _, p := range plan {
+				if p.supported {
+					supported++
+				}
+			}
+			if supported != tc.supported {
+				t.Fatalf("planTests returned %d supported tests, want %d", supported, tc.supported)
+			}
+		})
+	}
+}
+
+func TestPlannedTestTime(t *testing.T) {
+	p := plannedTest{nc: config.Config{Duration: 10, Samples: 3, Cooldown: 5, WarmupDuration: 30}}
+	// 3 samples, 2 cooldowns between them, the warm-up and its cooldown.
+	if got, want := p.testTime(), 75*time.Second; got != want {
+		t.Fatalf("testTime returned %s, want %s", got, want)
+	}
+}
+
+// failingDriver returns unparsable output on its first run, and fails to run afterwards.
+type failingDriver struct {
+	runs int
+}
+
+func (d *failingDriver) IsTestSupported() bool { return true }
+
+func (d *failingDriver) Run(_ *kubernetes.Clientset, _ rest.Config, _ config.Config, _ apiv1.PodList, _ string, _ *config.PerfScenarios, _ bool) (bytes.Buffer, error) {
+	d.runs++
+	if d.runs > 1 {
+		return bytes.Buffer{}, fmt.Errorf("command terminated with exit code %d", d.runs)
+	}
+	return *bytes.NewBufferString("garbage"), nil
+}
+
+func (d *failingDriver) ParseResults(_ *bytes.Buffer, _ config.Config) (sample.Sample, error) {
+	return sample.Sample{}, fmt.Errorf("unable to parse the output")
+}
+
+// flakyDriver fails to run the test on its first run, and succeeds afterwards.
+type flakyDriver struct {
+	runs int
+}
+
+func (d *flakyDriver) IsTestSupported() bool { return true }
+
+func (d *flakyDriver) Run(_ *kubernetes.Clientset, _ rest.Config, _ config.Config, _ apiv1.PodList, _ string, _ *config.PerfScenarios, _ bool) (bytes.Buffer, error) {
+	d.runs++
+	if d.runs == 1 {
+		return bytes.Buffer{}, fmt.Errorf("command terminated with exit code 1")
+	}
+	return *bytes.NewBufferString("10"), nil
+}
+
+func (d *flakyDriver) ParseResults(_ *bytes.Buffer, _ config.Config) (sample.Sample, error) {
+	return sample.Sample{Throughput: 10}, nil
+}
+
+func TestRunSampleRetries(t *testing.T) {
+	d := &failingDriver{}
+	_, err := runSample(d, "netperf", &config.PerfScenarios{}, config.Config{Profile: "TCP_STREAM"}, apiv1.PodList{}, "10.0.0.1", false)
+	if err == nil {
+		t.Fatal("expected runSample to fail")
+	}
+	if d.runs != retry+1 {
+		t.Fatalf("runSample ran the test %d times, want %d", d.runs, retry+1)
+	}
+	if !strings.Contains(err.Error(), fmt.Sprintf("exit code %d", retry+1)) {
+		t.Fatalf("runSample error %q does not carry the last failure", err)
+	}
+
+	flaky := &flakyDriver{}
+	nr, err := runSample(flaky, "netperf", &config.PerfScenarios{}, config.Config{Profile: "TCP_STREAM"}, apiv1.PodList{}, "10.0.0.1", false)
+	if err != nil {
+		t.Fatalf("runSample did not retry a test which failed to run first: %v", err)
+	}
+	if flaky.runs != 2 || nr.Throughput != 10 {
+		t.Fatalf("runSample ran the test %d times with throughput %f, want 2 runs and 10", flaky.runs, nr.Throughput)
+	}
+}
@@ -0,0 +1,273 @@
+package main
+
+import (
+	encodeJson "encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/config"
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/drivers"
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/k8s"
+	log "github.com/cloud-bulldozer/k8s-netperf/pkg/logging"
+	result "github.com/cloud-bulldozer/k8s-netperf/pkg/results"
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+var (
+	meshDriver      string
+	meshSelector    string
+	meshConcurrency int
+	meshPairCount   int
+	meshTolerance   float64
+)
+
+// meshPair is an ordered pair of nodes, the client runs on Client and the server on Server.
+type meshPair struct {
+	Client string
+	Server string
+}
+
+// meshPairs returns the ordered pairs of the nodes, or n of them sampled at random when 0 < n < all pairs.
+func meshPairs(nodes []string, n int, rng *rand.Rand) []meshPair {
+	var pairs []meshPair
+	for _, c := range nodes {
+		for _, s := range nodes {
+			if c != s {
+				pairs = append(pairs, meshPair{Client: c, Server: s})
+			}
+		}
+	}
+	if n > 0 && n < len(pairs) {
+		rng.Shuffle(len(pairs), func(i, j int) { pairs[i], pairs[j] = pairs[j], pairs[i] })
+		pairs = pairs[:n]
+		sort.Slice(pairs, func(i, j int) bool {
+			if pairs[i].Client != pairs[j].Client {
+				return pairs[i].Client < pairs[j].Client
+			}
+			return pairs[i].Server < pairs[j].Server
+		})
+	}
+	return pairs
+}
+
+// scheduleMesh runs the pairs with at most concurrency tests at a time, and never two
+// tests on the same node at a time, so that concurrent tests do not share a NIC.
+// returns the results in the order of the pairs.
+func scheduleMesh(pairs []meshPair, concurrency int, run func(meshPair) result.MeshResult) []result.MeshResult {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	results := make([]result.MeshResult, len(pairs))
+	pending := make([]int, len(pairs))
+	for i := range pairs {
+		pending[i] = i
+	}
+	busy := make(map[string]bool)
+	done := make(chan int)
+	running := 0
+	for len(pending) > 0 || running > 0 {
+		var waiting []int
+		for _, i := range pending {
+			p := pairs[i]
+			if running >= concurrency || busy[p.Client] || busy[p.Server] {
+				waiting = append(waiting, i)
+				continue
+			}
+			busy[p.Client], busy[p.Server] = true, true
+			running++
+			go func(i int) {
+				results[i] = run(pairs[i])
+				done <- i
+			}(i)
+		}
+		pending = waiting
+		i := <-done
+		running--
+		busy[pairs[i].Client], busy[pairs[i].Server] = false, false
+	}
+	return results
+}
+
+// runMeshPair runs the test of nc from the mesh pod of the client node to the mesh pod of the server node.
+func runMeshPair(s *config.PerfScenarios, nc config.Config, driver drivers.Driver, driverName string, client, server apiv1.Pod) result.MeshResult {
+	metric, higherIsBetter := result.MeshMetric(nc.Profile)
+	r := result.MeshResult{
+		Driver:      driverName,
+		Profile:     nc.Profile,
+		MessageSize: nc.MessageSize,
+		Parallelism: nc.Parallelism,
+		Client:      client.Spec.NodeName,
+		Server:      server.Spec.NodeName,
+		Metric:      metric,
+	}
+	var values []float64
+	for i := 0; i < nc.Samples; i++ {
+		out, err := driver.Run(s.ClientSet, s.RestConfig, nc, apiv1.PodList{Items: []apiv1.Pod{client}}, server.Status.PodIP, s, false)
+		if err != nil {
+			r.Reason = err.Error()
+			return r
+		}
+		sample, err := driver.ParseResults(&out, nc)
+		if err != nil {
+			r.Reason = err.Error()
+			return r
+		}
+		if higherIsBetter {
+			values = append(values, sample.Throughput)
+		} else {
+			values = append(values, sample.Latency99ptile)
+		}
+	}
+	r.Value, _ = result.Average(values)
+	log.Debugf("%s %s from %s to %s: %.2f %s", driverName, nc.Profile, r.Client, r.Server, r.Value, metric)
+	return r
+}
+
+var meshCmd = &cobra.Command{
+	Use:   "mesh",
+	Short: "Run a short test between every pair of nodes and flag the outlier pairs",
+	Long: `Run a short test between every pair of nodes, or a sampled subset of the pairs, and flag
+the pairs which are worse than the median of the fleet, e.g. to find a bad NIC, cable or ToR.
+
+A DaemonSet runs a server on every node matching --node-selector. Every test of the config file
+is run from each node to each other node, with at most --concurrency tests at a time, and never
+two tests on the same node at a time. The result is a client x server matrix per test.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if json {
+			log.SetError()
+		}
+		if meshConcurrency < 1 {
+			log.Fatal("--concurrency must be at least 1")
+		}
+		driver := meshDriver
+		if driver == "iperf" {
+			driver = "iperf3"
+		}
+		cf, err := config.ParseConfig(cfgfile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		cfg := cf.Tests
+		if err := applySettings(cmd, cf); err != nil {
+			log.Fatal(err)
+		}
+		for i := range cfg {
+			if cfg[i].Samples < 1 {
+				cfg[i].Samples = 1
+			}
+			cfg[i].Metric, _ = result.MeshMetric(cfg[i].Profile)
+		}
+		uid := id
+		if uid == "" {
+			uid = uuid.New().String()
+		}
+		ns := namespace
+		if ns == "" {
+			ns = k8s.NamespaceForRun(uid)
+		}
+		if err := k8s.SetNamespace(ns); err != nil {
+			log.Fatal(err)
+		}
+		k8s.SetRunUUID(uid)
+		kconfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+			clientcmd.NewDefaultClientConfigLoadingRules(),
+			&clientcmd.ConfigOverrides{})
+		rconfig, err := kconfig.ClientConfig()
+		if err != nil {
+			log.Fatal(err)
+		}
+		client, err := kubernetes.NewForConfig(rconfig)
+		if err != nil {
+			log.Fatal(err)
+		}
+		// Every cluster resource created from here on is recorded in the ledger,
+		// and torn down on any exit path, including interrupts.
+		run := newRun(uid)
+		ledger, teardown, fail := run.ledger, run.teardown, run.fail
+		if err := k8s.CheckNamespace(client, uid); err != nil {
+			fail(err)
+		}
+		s := config.PerfScenarios{
+			Configs:          cfg,
+			RequestedDrivers: []string{driver},
+			Privileged:       privileged,
+			RestConfig:       *rconfig,
+			ClientSet:        client,
+		}
+		if err := ledger.CreateNamespace(client, func() error { return k8s.BuildInfra(client, false, uid) }); err != nil {
+			fail(err)
+		}
+		mesh, err := k8s.DeployMesh(client, &s, meshSelector)
+		if err != nil {
+			fail(err)
+		}
+		var nodes []string
+		for n := range mesh {
+			nodes = append(nodes, n)
+		}
+		sort.Strings(nodes)
+		pairs := meshPairs(nodes, meshPairCount, rand.New(rand.NewSource(time.Now().UnixNano())))
+		log.Infof("🕸️  Running %d tests between %d of the %d pairs of %d nodes, %d at a time", len(cfg), len(pairs), len(nodes)*(len(nodes)-1), len(nodes), meshConcurrency)
+		var results []result.MeshResult
+		for _, nc := range cfg {
+			d, err := drivers.NewDriver(driver, nc)
+			if err != nil {
+				fail(err)
+			}
+			if !d.IsTestSupported() {
+				log.Warnf("Test %s is not supported with driver %s. Skipping.", nc.Profile, driver)
+				continue
+			}
+			results = append(results, scheduleMesh(pairs, meshConcurrency, func(p meshPair) result.MeshResult {
+				return runMeshPair(&s, nc, d, driver, mesh[p.Client], mesh[p.Server])
+			})...)
+		}
+		result.FlagMeshOutliers(results, meshTolerance)
+		if json {
+			out, err := encodeJson.MarshalIndent(results, "", "  ")
+			if err != nil {
+				fail(err)
+			}
+			fmt.Println(string(out))
+		} else {
+			result.ShowMeshMatrix(results)
+		}
+		retCode := 0
+		var bad []string
+		for _, r := range results {
+			if r.Failed() || r.Outlier {
+				bad = append(bad, fmt.Sprintf("%s->%s", r.Client, r.Server))
+				retCode = 1
+			}
+		}
+		if retCode != 0 {
+			log.Errorf("😥 %d outlier or failed pairs: %s", len(bad), strings.Join(bad, ", "))
+		}
+		teardown()
+		os.Exit(retCode)
+	},
+}
+
+func init() {
+	meshCmd.Flags().StringVar(&cfgfile, "config", "netperf.yml", "K8s netperf Configuration File, keep the tests short")
+	meshCmd.Flags().StringVar(&meshDriver, "driver", "netperf", "Load driver, netperf, iperf3 or uperf (default netperf)")
+	meshCmd.Flags().StringVar(&meshSelector, "node-selector", "node-role.kubernetes.io/worker=", "Label selector of the nodes of the mesh")
+	meshCmd.Flags().IntVar(&meshConcurrency, "concurrency", 4, "Maximum number of pairs tested at a time (default 4)")
+	meshCmd.Flags().IntVar(&meshPairCount, "pairs", 0, "Number of pairs sampled at random, all the pairs when 0 (default 0)")
+	meshCmd.Flags().Float64Var(&meshTolerance, "outlier-tolerance", 20, "Percent below the fleet median throughput, or above the fleet median latency, from which a pair is an outlier (default 20)")
+	meshCmd.Flags().StringVar(&namespace, "namespace", "", "Namespace to deploy the mesh in (default netperf-<first 8 characters of the UUID>)")
+	meshCmd.Flags().StringVar(&id, "uuid", "", "User provided UUID")
+	meshCmd.Flags().BoolVar(&clean, "clean", true, "Clean-up resources created by k8s-netperf (default true)")
+	meshCmd.Flags().BoolVar(&json, "json", false, "Instead of the matrix, return the results of the pairs as JSON to stdout (default false)")
+	meshCmd.Flags().BoolVar(&privileged, "privileged", false, "Run pods with privileged security context (default false)")
+	addSettingsFlags(meshCmd)
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	result "github.com/cloud-bulldozer/k8s-netperf/pkg/results"
+)
+
+func TestMeshPairs(t *testing.T) {
+	nodes := []string{"a", "b", "c", "d"}
+	all := meshPairs(nodes, 0, rand.New(rand.NewSource(1)))
+	if len(all) != 12 {
+		t.Fatalf("expected 12 ordered pairs, got %d", len(all))
+	}
+	seen := make(map[meshPair]bool)
+	for _, p := range all {
+		if p.Client == p.Server {
+			t.Fatalf("a node must not be paired with itself: %+v", p)
+		}
+		seen[p] = true
+	}
+	if len(seen) != 12 {
+		t.Fatalf("expected 12 distinct pairs, got %d", len(seen))
+	}
+	sampled := meshPairs(nodes, 5, rand.New(rand.NewSource(1)))
+	if len(sampled) != 5 {
+		t.Fatalf("expected 5 sampled pairs, got %d", len(sampled))
+	}
+	for _, p := range sampled {
+		if !seen[p] {
+			t.Fatalf("sampled an unknown pair %+v", p)
+		}
+	}
+}
+
+func TestScheduleMeshBoundsConcurrency(t *testing.T) {
+	nodes := []string{"a", "b", "c", "d", "e", "f"}
+	pairs := meshPairs(nodes, 0, nil)
+	var mu sync.Mutex
+	busy := make(map[string]bool)
+	running, peak := 0, 0
+	results := scheduleMesh(pairs, 2, func(p meshPair) result.MeshResult {
+		mu.Lock()
+		if busy[p.Client] || busy[p.Server] {
+			mu.Unlock()
+			t.Errorf("pair %+v started while one of its nodes was busy", p)
+			return result.MeshResult{}
+		}
+		busy[p.Client], busy[p.Server] = true, true
+		running++
+		if running > peak {
+			peak = running
+		}
+		mu.Unlock()
+		time.Sleep(time.Millisecond)
+		mu.Lock()
+		busy[p.Client], busy[p.Server] = false, false
+		running--
+		mu.Unlock()
+		return result.MeshResult{Client: p.Client, Server: p.Server}
+	})
+	if peak > 2 {
+		t.Fatalf("expected at most 2 concurrent tests, got %d", peak)
+	}
+	for i, r := range results {
+		if r.Client != pairs[i].Client || r.Server != pairs[i].Server {
+			t.Fatalf("result %d is for %s->%s, want %+v", i, r.Client, r.Server, pairs[i])
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/config"
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/drivers"
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/k8s"
+	log "github.com/cloud-bulldozer/k8s-netperf/pkg/logging"
+	result "github.com/cloud-bulldozer/k8s-netperf/pkg/results"
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/sample"
+	"github.com/google/uuid"
+)
+
+// migrationPolls bounds how many times, every 10 seconds, the output of a migration test
+// is polled once its duration is over.
+const migrationPolls = 30
+
+// runMigration runs the test on the client VMI in the background, live-migrates the server
+// or client VMI MigrateAfter seconds into it, and reports the disruption from the time
+// series of the test and of ping probes towards the server.
+func runMigration(driver drivers.Driver, s *config.PerfScenarios, nc config.Config, serverIP string) (sample.Sample, *result.Migration, error) {
+	// A connection of its own, which is re-established when the client VMI moves.
+	vm, err := k8s.ConnectToVM(s)
+	if err != nil {
+		return sample.Sample{}, nil, err
+	}
+	defer func() {
+		if err := vm.Close(); err != nil {
+			log.Warnf("Error closing VM client: %v", err)
+		}
+	}()
+	config.Show(nc, "netperf")
+	prefix := "/tmp/migration-" + uuid.New().String()
+	if _, err := vm.Run(drivers.MigrationCommand(nc, serverIP, prefix)); err != nil {
+		return sample.Sample{}, nil, fmt.Errorf("unable to start the test on the client VMI: %v", err)
+	}
+	start := time.Now()
+	time.Sleep(time.Duration(nc.MigrateAfter) * time.Second)
+	m := &result.Migration{VMI: nc.Migrate, Start: time.Since(start).Seconds()}
+	state, migErr := k8s.MigrateVMI(s, nc.Migrate)
+	m.End = time.Since(start).Seconds()
+	if state != nil {
+		m.SourceNode, m.TargetNode = state.SourceNode, state.TargetNode
+	}
+	// The next test must not overlap this one, even when the migration failed.
+	time.Sleep(time.Until(start.Add(time.Duration(nc.Duration) * time.Second)))
+	out, err := migrationOutput(s, &vm, prefix)
+	if migErr != nil {
+		return sample.Sample{}, nil, migErr
+	}
+	if err != nil {
+		return sample.Sample{}, nil, err
+	}
+	parts := strings.SplitN(out, drivers.MigrationSeparator+"\n", 3)
+	if len(parts) != 3 {
+		return sample.Sample{}, nil, fmt.Errorf("unexpected output of the migration test: %s", out)
+	}
+	vmStart, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil {
+		return sample.Sample{}, nil, fmt.Errorf("unexpected start time of the migration test %q: %v", parts[0], err)
+	}
+	nr, err := driver.ParseResults(bytes.NewBufferString(parts[1]), nc)
+	if err != nil {
+		return nr, nil, err
+	}
+	m.Throughput = result.ParseInterim(parts[1], vmStart)
+	m.RTT, m.Lost, m.LossPercent = result.ParseProbes(parts[2], vmStart)
+	if len(m.RTT) == 0 {
+		log.Warnf("No ping probe reached the server, the probe loss and latency spike are not measured: %s", strings.TrimSpace(parts[2]))
+	}
+	m.Analyze(float64(nc.Duration), drivers.DemoInterval, drivers.ProbeInterval)
+	if m.End > float64(nc.Duration) {
+		log.Warnf("😥 The migration ended %.1fs after the test, the disruption may be underestimated, increase the duration", m.End-float64(nc.Duration))
+	}
+	log.Infof("🚚 Migration of the %s VMI from %s to %s: downtime %.2fs, probe loss %.2f%%, latency spike %.2fs", m.VMI, m.SourceNode, m.TargetNode, m.Downtime, m.LossPercent, m.LatencySpike)
+	return nr, m, nil
+}
+
+// migrationOutput waits for the migration test started with the prefix to finish, and returns
+// its output. The connection to the client VMI is re-established when it broke.
+func migrationOutput(s *config.PerfScenarios, vm *config.VMExecutor, prefix string) (string, error) {
+	var err error
+	for i := 0; i < migrationPolls; i++ {
+		var out []byte
+		out, err = (*vm).Run(drivers.MigrationOutputCommand(prefix))
+		if err == nil && len(out) > 0 {
+			return string(out), nil
+		}
+		if err != nil {
+			log.Debugf("Reconnecting to the client VMI: %v", err)
+			if cerr := (*vm).Close(); cerr != nil {
+				log.Debugf("Error closing VM client: %v", cerr)
+			}
+			if c, cerr := k8s.ConnectToVM(s); cerr == nil {
+				*vm = c
+			}
+		}
+		time.Sleep(10 * time.Second)
+	}
+	if err != nil {
+		return "", fmt.Errorf("unable to read the output of the migration test: %v", err)
+	}
+	return "", fmt.Errorf("the migration test did not finish")
+}
+
+// afterMigration points the scenario at the pods the VMIs run in after a live migration, and
+// reconnects to the client VMI when it moved.
+func afterMigration(s *config.PerfScenarios, role string) {
+	if err := k8s.RefreshVMPods(s); err != nil {
+		log.Warnf("Unable to find the pods of the VMIs after the migration: %v", err)
+	}
+	if role != config.MigrateClient || s.VMClientExecutor == nil {
+		return
+	}
+	if err := s.VMClientExecutor.Close(); err != nil {
+		log.Debugf("Error closing VM client: %v", err)
+	}
+	vm, err := k8s.ConnectToVM(s)
+	if err != nil {
+		log.Errorf("😥 Unable to reconnect to the client VMI after its migration: %v", err)
+		s.VMClientExecutor = nil
+		return
+	}
+	s.VMClientExecutor = vm
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/config"
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/drivers"
+	log "github.com/cloud-bulldozer/k8s-netperf/pkg/logging"
+	result "github.com/cloud-bulldozer/k8s-netperf/pkg/results"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+// plannedTest is one test execution of the run, as computed by planTests.
+type plannedTest struct {
+	nc          config.Config
+	driver      string
+	hostNetwork bool
+	path        string
+	// runtimeClass is the runtime class of the pods, empty for the default runtime.
+	runtimeClass string
+	supported    bool
+}
+
+// testTime returns the upper bound of the time spent running the test, warm-up and cooldown included.
+func (p plannedTest) testTime() time.Duration {
+	samples := p.nc.Samples
+	if p.nc.AutoSamples {
+		samples = p.nc.MaxSamples
+	}
+	secs := samples*p.nc.Duration + (samples-1)*p.nc.Cooldown
+	if p.nc.WarmupDuration > 0 {
+		secs += p.nc.WarmupDuration + p.nc.Cooldown
+	} else {
+		secs += p.nc.WarmupSamples * (p.nc.Duration + p.nc.Cooldown)
+	}
+	return time.Duration(secs) * time.Second
+}
+
+// topology describes the topology of the test, e.g. incast 4:1, empty for a single client and server.
+func (p plannedTest) topology() string {
+	return result.TopologyLabel(result.Data{Config: p.nc})
+}
+
+// planTests expands the configurations into the tests the run command would execute,
+// following the same order as the run. sandbox is the runtime class the pod tests also run
+// with, none when empty.
+func planTests(cfg []config.Config, requestedDrivers []string, hostNetwork, hostNetOnly, pod, vm, mixed bool, sandbox string) []plannedTest {
+	var plan []plannedTest
+	add := func(nc config.Config, driver string, hostNet bool, path string, runtimeClass string) {
+		supported := false
+		if d, err := drivers.NewDriver(driver, nc); err == nil {
+			supported = d.IsTestSupported()
+		}
+		// Incast and fan-out tests only run pod to pod, see executeWorkload.
+		if supported && nc.Topology() != "" {
+			supported = !hostNet && path == result.PathPodToPod && !nc.Service && checkGroupDriver(driver, nc) == nil
+		}
+		// Live migration tests only run between VMs, with netperf.
+		if supported && nc.Migrate != "" {
+			supported = path == result.PathVMToVM && driver == "netperf"
+		}
+		plan = append(plan, plannedTest{nc: nc, driver: driver, hostNetwork: hostNet, path: path, runtimeClass: runtimeClass, supported: supported})
+	}
+	if pod {
+		for _, nc := range cfg {
+			for _, driver := range requestedDrivers {
+				if hostNetwork && !nc.Service {
+					add(nc, driver, true, result.PathPodToPod, "")
+				}
+				if !hostNetOnly {
+					add(nc, driver, false, result.PathPodToPod, "")
+				}
+				if sandbox != "" && !nc.Service && nc.Topology() == "" {
+					add(nc, driver, false, result.PathPodToPod, sandbox)
+				}
+			}
+		}
+	}
+	if vm && !hostNetOnly {
+		for _, nc := range cfg {
+			for _, driver := range requestedDrivers {
+				add(nc, driver, false, result.PathVMToVM, "")
+			}
+		}
+	}
+	if mixed && !hostNetOnly {
+		for _, nc := range cfg {
+			for _, driver := range requestedDrivers {
+				add(nc, driver, false, result.PathPodToVM, "")
+				add(nc, driver, false, result.PathVMToPod, "")
+			}
+		}
+	}
+	return plan
+}
+
+var planCmd = &cobra.Command{
+	Use:   "plan",
+	Short: "Show the tests a run would execute, without connecting to the cluster",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		validateRunFlags(cmd)
+		cf, err := config.ParseConfig(cfgfile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		cfg := cf.Tests
+		plan := planTests(cfg, requestedDriverNames(cmd), full || hostNetOnly, hostNetOnly, pod, vm, mixed, sandbox)
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"#", "Driver", "Scenario", "Parallelism", "Host Network", "Path", "Runtime Class", "Service", "Topology", "Message Size", "Burst", "Duration", "Samples", "Warm-up", "Supported"})
+		table.SetAlignment(tablewriter.ALIGN_LEFT)
+		table.SetAutoWrapText(false)
+		var total time.Duration
+		n := 0
+		for _, p := range plan {
+			samples := strconv.Itoa(p.nc.Samples)
+			if p.nc.AutoSamples {
+				samples = fmt.Sprintf("auto (%d-%d)", p.nc.MinSamples, p.nc.MaxSamples)
+			}
+			idx := "-"
+			if p.supported {
+				n++
+				idx = strconv.Itoa(n)
+				total += p.testTime()
+			}
+			table.Append([]string{idx, p.driver, p.nc.Profile, strconv.Itoa(p.nc.Parallelism), strconv.FormatBool(p.hostNetwork), p.path, result.RuntimeLabel(result.Data{RuntimeClass: p.runtimeClass}), strconv.FormatBool(p.nc.Service), p.topology(), strconv.Itoa(p.nc.MessageSize), strconv.Itoa(p.nc.Burst), strconv.Itoa(p.nc.Duration), samples, p.nc.Warmup, strconv.FormatBool(p.supported)})
+		}
+		table.Render()
+		fmt.Printf("%d tests, up to %s of test time (deployment and VM boot not included)\n", n, total)
+	},
+}
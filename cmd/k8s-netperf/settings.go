@@ -0,0 +1,89 @@
+package main
+
+import (
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/config"
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/k8s"
+	"github.com/spf13/cobra"
+)
+
+// The image, resources and scheduling settings of the command line, which override the
+// sections of the configuration file.
+var (
+	imageFlags      config.Image
+	resourcesFlags  config.Resources
+	schedulingFlags config.Scheduling
+	tolerationsFlag []string
+)
+
+// addSettingsFlags registers the image, resources and scheduling flags on cmd.
+func addSettingsFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&imageFlags.Name, "image", k8s.DefaultImage, "Container image of the k8s-netperf pods")
+	cmd.Flags().StringVar(&imageFlags.Mirror, "image-mirror", "", "Registry to pull the image from instead of the registry of the image name, e.g. for disconnected clusters")
+	cmd.Flags().StringVar(&imageFlags.Digest, "image-digest", "", "Pin the image to a digest (sha256:...)")
+	cmd.Flags().StringVar(&imageFlags.PullPolicy, "image-pull-policy", "Always", "Pull policy of the image, Always, IfNotPresent or Never (default Always)")
+	cmd.Flags().StringSliceVar(&imageFlags.PullSecrets, "image-pull-secret", nil, "Secret to pull the image with, as namespace/name, copied in the namespace of the run (can be repeated)")
+
+	cmd.Flags().StringVar(&resourcesFlags.CPU, "pod-cpu", "", "CPU request of each container of the pods, e.g. 2")
+	cmd.Flags().StringVar(&resourcesFlags.Memory, "pod-memory", "", "Memory request of each container of the pods, e.g. 1Gi")
+	cmd.Flags().StringVar(&resourcesFlags.CPULimit, "pod-cpu-limit", "", "CPU limit of each container of the pods")
+	cmd.Flags().StringVar(&resourcesFlags.MemoryLimit, "pod-memory-limit", "", "Memory limit of each container of the pods")
+	cmd.Flags().BoolVar(&resourcesFlags.Guaranteed, "guaranteed", false, "Run the pods with the Guaranteed QoS class, the limits are the requests and the CPUs an integer, pinned by the static CPU Manager policy (default false)")
+	cmd.Flags().StringVar(&resourcesFlags.Hugepages, "pod-hugepages", "", "Hugepages of each container of the pods, e.g. 1Gi, mounted in /dev/hugepages")
+	cmd.Flags().StringVar(&resourcesFlags.HugepageSize, "pod-hugepage-size", "", "Size of the hugepages of the pods, 2Mi or 1Gi (default 2Mi)")
+	cmd.Flags().StringVar(&resourcesFlags.RuntimeClassName, "runtime-class", "", "Runtime class of the pods, other than the host network pods")
+	cmd.Flags().StringVar(&resourcesFlags.SchedulerName, "scheduler-name", "", "Scheduler of the pods, e.g. the topology-aware scheduler of the NUMA Resources Operator")
+
+	cmd.Flags().StringArrayVar(&tolerationsFlag, "toleration", nil, "Toleration of the pods and VMs, as key[=value][:effect], e.g. dedicated=netperf:NoSchedule (can be repeated)")
+	cmd.Flags().StringArrayVar(&schedulingFlags.NodeSelectorTerms, "node-selector-term", nil, "Label selector of nodes the pods and VMs may run on besides the worker nodes, e.g. node-role.kubernetes.io/gpu= (can be repeated)")
+	cmd.Flags().StringVar(&schedulingFlags.PriorityClassName, "priority-class", "", "Priority class of the pods and VMs")
+}
+
+// override sets *dst to v when the flag name is set on the command line.
+func override[T any](cmd *cobra.Command, name string, dst *T, v T) {
+	if cmd.Flags().Changed(name) {
+		*dst = v
+	}
+}
+
+// applySettings sets the image, the resources and the scheduling of the pods and VMs from
+// the sections of the configuration file, overridden by the flags set on the command line.
+func applySettings(cmd *cobra.Command, f config.File) error {
+	img := f.Image
+	override(cmd, "image", &img.Name, imageFlags.Name)
+	override(cmd, "image-mirror", &img.Mirror, imageFlags.Mirror)
+	override(cmd, "image-digest", &img.Digest, imageFlags.Digest)
+	override(cmd, "image-pull-policy", &img.PullPolicy, imageFlags.PullPolicy)
+	override(cmd, "image-pull-secret", &img.PullSecrets, imageFlags.PullSecrets)
+	if err := k8s.SetImage(img); err != nil {
+		return err
+	}
+
+	r := f.Resources
+	override(cmd, "pod-cpu", &r.CPU, resourcesFlags.CPU)
+	override(cmd, "pod-memory", &r.Memory, resourcesFlags.Memory)
+	override(cmd, "pod-cpu-limit", &r.CPULimit, resourcesFlags.CPULimit)
+	override(cmd, "pod-memory-limit", &r.MemoryLimit, resourcesFlags.MemoryLimit)
+	override(cmd, "guaranteed", &r.Guaranteed, resourcesFlags.Guaranteed)
+	override(cmd, "pod-hugepages", &r.Hugepages, resourcesFlags.Hugepages)
+	override(cmd, "pod-hugepage-size", &r.HugepageSize, resourcesFlags.HugepageSize)
+	override(cmd, "runtime-class", &r.RuntimeClassName, resourcesFlags.RuntimeClassName)
+	override(cmd, "scheduler-name", &r.SchedulerName, resourcesFlags.SchedulerName)
+	if err := k8s.SetResources(r); err != nil {
+		return err
+	}
+
+	sc := f.Scheduling
+	if cmd.Flags().Changed("toleration") {
+		sc.Tolerations = nil
+		for _, t := range tolerationsFlag {
+			tol, err := k8s.ParseToleration(t)
+			if err != nil {
+				return err
+			}
+			sc.Tolerations = append(sc.Tolerations, tol)
+		}
+	}
+	override(cmd, "node-selector-term", &sc.NodeSelectorTerms, schedulingFlags.NodeSelectorTerms)
+	override(cmd, "priority-class", &sc.PriorityClassName, schedulingFlags.PriorityClassName)
+	return k8s.SetScheduling(sc)
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"fmt"
+
+	cmdVersion "github.com/cloud-bulldozer/go-commons/v2/version"
+	"github.com/spf13/cobra"
+)
+
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Print the k8s-netperf version",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		printVersion()
+	},
+}
+
+func printVersion() {
+	fmt.Println("Version:", cmdVersion.Version)
+	fmt.Println("Git Commit:", cmdVersion.GitCommit)
+	fmt.Println("Build Date:", cmdVersion.BuildDate)
+	fmt.Println("Go Version:", cmdVersion.GoVersion)
+	fmt.Println("OS/Arch:", cmdVersion.OsArch)
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/cloud-bulldozer/go-commons/v2/indexers"
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/config"
 	"github.com/cloud-bulldozer/k8s-netperf/pkg/logging"
 	"github.com/cloud-bulldozer/k8s-netperf/pkg/metrics"
 	result "github.com/cloud-bulldozer/k8s-netperf/pkg/results"
@@ -19,50 +20,78 @@ const ltcyMetric = "usec"
 
 // Doc struct of the JSON document to be indexed
 type Doc struct {
-	UUID               string           `json:"uuid"`
-	Timestamp          time.Time        `json:"timestamp"`
-	HostNetwork        bool             `json:"hostNetwork"`
-	Driver             string           `json:"driver"`
-	Parallelism        int              `json:"parallelism"`
-	Profile            string           `json:"profile"`
-	Duration           int              `json:"duration"`
-	Service            bool             `json:"service"`
-	Local              bool             `json:"local"`
-	Virt               bool             `json:"virt"`
-	AcrossAZ           bool             `json:"acrossAZ"`
-	Samples            int              `json:"samples"`
-	Messagesize        int              `json:"messageSize"`
-	Burst              int              `json:"burst"`
-	Throughput         float64          `json:"throughput"`
-	Latency            float64          `json:"latency"`
-	TputMetric         string           `json:"tputMetric"`
-	LtcyMetric         string           `json:"ltcyMetric"`
-	TCPRetransmit      float64          `json:"tcpRetransmits"`
-	UDPLossPercent     float64          `json:"udpLossPercent"`
-	ToolVersion        string           `json:"toolVersion"`
-	ToolGitCommit      string           `json:"toolGitCommit"`
-	Metadata           result.Metadata  `json:"metadata"`
-	ServerNodeCPU      metrics.NodeCPU  `json:"serverCPU"`
-	ServerCPUCollected bool             `json:"serverCPUCollected"`
-	ServerPodCPU       []metrics.PodCPU `json:"serverPods"`
-	ServerPodMem       []metrics.PodMem `json:"serverPodsMem"`
-	ClientNodeCPU      metrics.NodeCPU  `json:"clientCPU"`
-	ClientCPUCollected bool             `json:"clientCPUCollected"`
-	ClientPodCPU       []metrics.PodCPU `json:"clientPods"`
-	ClientPodMem       []metrics.PodMem `json:"clientPodsMem"`
-	Confidence         []float64        `json:"confidence"`
-	ServerNodeInfo     metrics.NodeInfo `json:"serverNodeInfo"`
-	ClientNodeInfo     metrics.NodeInfo `json:"clientNodeInfo"`
-	ServerVSwitchCpu   float64          `json:"serverVswtichCpu"`
-	ServerVSwitchMem   float64          `json:"serverVswitchMem"`
-	ClientVSwitchCpu   float64          `json:"clientVswtichCpu"`
-	ClientVSwiitchMem  float64          `json:"clientVswitchMem"`
-	ExternalServer     bool             `json:"externalServer"`
-	UdnInfo            string           `json:"udnInfo"`
-	BridgeInfo         string           `json:"bridgeInfo"`
-	SriovInfo          string           `json:"sriovInfo"`
-	MacvlanInfo        string           `json:"macvlanInfo"`
-	LocalnetInfo       string           `json:"localnetInfo"`
+	UUID               string            `json:"uuid"`
+	Timestamp          time.Time         `json:"timestamp"`
+	HostNetwork        bool              `json:"hostNetwork"`
+	Driver             string            `json:"driver"`
+	Parallelism        int               `json:"parallelism"`
+	Profile            string            `json:"profile"`
+	Duration           int               `json:"duration"`
+	Service            bool              `json:"service"`
+	Local              bool              `json:"local"`
+	Virt               bool              `json:"virt"`
+	Path               string            `json:"path"`
+	RuntimeClass       string            `json:"runtimeClass,omitempty"`
+	VMSpec             *config.VMSpec    `json:"vmSpec,omitempty"`
+	QOSClass           string            `json:"qosClass,omitempty"`
+	AcrossAZ           bool              `json:"acrossAZ"`
+	Samples            int               `json:"samples"`
+	Messagesize        int               `json:"messageSize"`
+	Burst              int               `json:"burst"`
+	Throughput         float64           `json:"throughput"`
+	Latency            float64           `json:"latency"`
+	TputMetric         string            `json:"tputMetric"`
+	LtcyMetric         string            `json:"ltcyMetric"`
+	TCPRetransmit      float64           `json:"tcpRetransmits"`
+	UDPLossPercent     float64           `json:"udpLossPercent"`
+	ToolVersion        string            `json:"toolVersion"`
+	ToolGitCommit      string            `json:"toolGitCommit"`
+	Metadata           result.Metadata   `json:"metadata"`
+	ServerNodeCPU      metrics.NodeCPU   `json:"serverCPU"`
+	ServerCPUCollected bool              `json:"serverCPUCollected"`
+	ServerPodCPU       []metrics.PodCPU  `json:"serverPods"`
+	ServerPodMem       []metrics.PodMem  `json:"serverPodsMem"`
+	ClientNodeCPU      metrics.NodeCPU   `json:"clientCPU"`
+	ClientCPUCollected bool              `json:"clientCPUCollected"`
+	ClientPodCPU       []metrics.PodCPU  `json:"clientPods"`
+	ClientPodMem       []metrics.PodMem  `json:"clientPodsMem"`
+	Confidence         []float64         `json:"confidence"`
+	ServerNodeInfo     metrics.NodeInfo  `json:"serverNodeInfo"`
+	ClientNodeInfo     metrics.NodeInfo  `json:"clientNodeInfo"`
+	ServerVSwitchCpu   float64           `json:"serverVswtichCpu"`
+	ServerVSwitchMem   float64           `json:"serverVswitchMem"`
+	ClientVSwitchCpu   float64           `json:"clientVswtichCpu"`
+	ClientVSwiitchMem  float64           `json:"clientVswitchMem"`
+	ExternalServer     bool              `json:"externalServer"`
+	UdnInfo            string            `json:"udnInfo"`
+	BridgeInfo         string            `json:"bridgeInfo"`
+	SriovInfo          string            `json:"sriovInfo"`
+	MacvlanInfo        string            `json:"macvlanInfo"`
+	LocalnetInfo       string            `json:"localnetInfo"`
+	ThroughputStats    result.Stats      `json:"throughputStats"`
+	LatencyStats       result.Stats      `json:"latencyStats"`
+	ThroughputSamples  []float64         `json:"throughputSamples"`
+	LatencySamples     []float64         `json:"latencySamples"`
+	LatencyAvgSamples  []float64         `json:"latencyAvgSamples"`
+	Latency50Samples   []float64         `json:"latency50Samples"`
+	LossSamples        []float64         `json:"lossSamples"`
+	RetransmitSamples  []float64         `json:"retransmitSamples"`
+	Status             string            `json:"status"`
+	Reason             string            `json:"reason,omitempty"`
+	WarmupThroughput   []float64         `json:"warmupThroughput,omitempty"`
+	WarmupLatency      []float64         `json:"warmupLatency,omitempty"`
+	Topology           string            `json:"topology,omitempty"`
+	Incast             int               `json:"incast,omitempty"`
+	Fanout             int               `json:"fanout,omitempty"`
+	Endpoints          []string          `json:"endpoints,omitempty"`
+	EndpointThroughput [][]float64       `json:"endpointThroughputSamples,omitempty"`
+	Pairs              int               `json:"pairs,omitempty"`
+	Spread             bool              `json:"spread,omitempty"`
+	Fairness           float64           `json:"fairness,omitempty"`
+	FairnessSamples    []float64         `json:"fairnessSamples,omitempty"`
+	Migrate            string            `json:"migrate,omitempty"`
+	MigrateAfter       int               `json:"migrateAfter,omitempty"`
+	Migration          *result.Migration `json:"migration,omitempty"`
 }
 
 // Connect returns a client connected to the desired cluster.
@@ -86,8 +115,12 @@ func Connect(url, index string, skip bool) (*indexers.Indexer, error) {
 }
 
 // BuildDocs returns the documents that need to be indexed or an error.
+// Results loaded back from a JSON result keep their original timestamp.
 func BuildDocs(sr result.ScenarioResults, uuid string) ([]interface{}, error) {
 	time := time.Now().UTC()
+	if !sr.Timestamp.IsZero() {
+		time = sr.Timestamp
+	}
 
 	var docs []interface{}
 	if len(sr.Results) < 1 {
@@ -113,6 +146,10 @@ func BuildDocs(sr result.ScenarioResults, uuid string) ([]interface{}, error) {
 			Profile:            r.Profile,
 			Duration:           r.Duration,
 			Virt:               r.Virt,
+			Path:               result.PathLabel(r),
+			RuntimeClass:       r.RuntimeClass,
+			VMSpec:             r.VMSpec,
+			QOSClass:           r.QOSClass,
 			Samples:            r.Samples,
 			Service:            r.Service,
 			Local:              r.SameNode,
@@ -143,6 +180,35 @@ func BuildDocs(sr result.ScenarioResults, uuid string) ([]interface{}, error) {
 			SriovInfo:          r.SriovInfo,
 			MacvlanInfo:        r.MacvlanInfo,
 			LocalnetInfo:       r.LocalnetInfo,
+			ThroughputStats:    result.Summarize(r.ThroughputSummary),
+			LatencyStats:       result.Summarize(r.LatencySummary),
+			ThroughputSamples:  r.ThroughputSummary,
+			LatencySamples:     r.LatencySummary,
+			LatencyAvgSamples:  r.LatencyAvgSummary,
+			Latency50Samples:   r.Latency50Summary,
+			LossSamples:        r.LossSummary,
+			RetransmitSamples:  r.RetransmitSummary,
+			Status:             r.Status,
+			Reason:             r.Reason,
+			WarmupThroughput:   r.WarmupThroughputSummary,
+			WarmupLatency:      r.WarmupLatencySummary,
+			Topology:           r.Topology(),
+			Incast:             r.Incast,
+			Fanout:             r.Fanout,
+			Endpoints:          r.Endpoints,
+			EndpointThroughput: r.EndpointThroughputSummary,
+			Pairs:              r.Pairs,
+			Spread:             r.Spread,
+			FairnessSamples:    r.FairnessSummary,
+			Migrate:            r.Migrate,
+			MigrateAfter:       r.MigrateAfter,
+			Migration:          r.Migration,
+		}
+		if fairness, e := result.Average(r.FairnessSummary); e == nil {
+			d.Fairness = fairness
+		}
+		if d.Status == "" {
+			d.Status = result.StatusCompleted
 		}
 		UDPLossPercent, e := result.Average(r.LossSummary)
 		if e != nil {
@@ -185,6 +251,9 @@ func commonCsvHeaderFields() []string {
 		"Same node",
 		"Host Network",
 		"VM mode",
+		"Path",
+		"Runtime Class",
+		"QoS class",
 		"Service",
 		"External Server",
 		"UDN Info",
@@ -192,6 +261,7 @@ func commonCsvHeaderFields() []string {
 		"SR-IOV Info",
 		"Macvlan Info",
 		"Localnet Info",
+		"Topology",
 		"Duration",
 		"Parallelism",
 		"# of Samples",
@@ -214,6 +284,9 @@ func commonCsvDataFields(row result.Data) []string {
 		fmt.Sprint(row.SameNode),
 		fmt.Sprint(row.HostNetwork),
 		fmt.Sprint(row.Virt),
+		result.PathLabel(row),
+		result.RuntimeLabel(row),
+		row.QOSClass,
 		fmt.Sprint(row.Service),
 		fmt.Sprint(row.ExternalServer),
 		fmt.Sprint(row.UdnInfo),
@@ -221,6 +294,7 @@ func commonCsvDataFields(row result.Data) []string {
 		fmt.Sprint(row.SriovInfo),
 		fmt.Sprint(row.MacvlanInfo),
 		fmt.Sprint(row.LocalnetInfo),
+		result.TopologyLabel(row),
 		strconv.Itoa(row.Duration),
 		strconv.Itoa(row.Parallelism),
 		strconv.Itoa(row.Samples),
@@ -231,6 +305,22 @@ func commonCsvDataFields(row result.Data) []string {
 	}
 }
 
+// statsCsvFields returns the csv fields for the sample statistics.
+func statsCsvFields(st result.Stats) []string {
+	outliers := make([]string, len(st.Outliers))
+	for i, o := range st.Outliers {
+		outliers[i] = strconv.Itoa(o)
+	}
+	return []string{
+		fmt.Sprintf("%f", st.Median),
+		fmt.Sprintf("%f", st.Min),
+		fmt.Sprintf("%f", st.Max),
+		fmt.Sprintf("%f", st.StdDev),
+		fmt.Sprintf("%f", st.CV),
+		strings.Join(outliers, " "),
+	}
+}
+
 // Writes all the mertics to the archive.
 func writeArchive(vswitch, cpuarchive, podarchive, podmemarchive *csv.Writer, role string, row result.Data, podResults []metrics.PodCPU, podMem []metrics.PodMem) error {
 	roleFieldData := []string{role}
@@ -414,8 +504,8 @@ func WriteSpecificCSV(r result.ScenarioResults) error {
 }
 
 // WriteJSONResult sends the results as JSON to stdout
-func WriteJSONResult(r result.ScenarioResults) error {
-	docs, err := BuildDocs(r, "k8s-netperf")
+func WriteJSONResult(r result.ScenarioResults, uuid string) error {
+	docs, err := BuildDocs(r, uuid)
 	if err != nil {
 		return err
 	}
@@ -447,6 +537,18 @@ func WriteCSVResult(r result.ScenarioResults) error {
 		"Throughput Metric",
 		"99%tile Observed Latency",
 		"Latency Metric",
+		"Throughput Median",
+		"Throughput Min",
+		"Throughput Max",
+		"Throughput StdDev",
+		"Throughput CV",
+		"Throughput Outliers",
+		"99%tile Latency Median",
+		"99%tile Latency Min",
+		"99%tile Latency Max",
+		"99%tile Latency StdDev",
+		"99%tile Latency CV",
+		"99%tile Latency Outliers",
 	)
 
 	if err := archive.Write(data); err != nil {
@@ -461,9 +563,137 @@ func WriteCSVResult(r result.ScenarioResults) error {
 			fmt.Sprint(lavg),
 			"usec",
 		)
+		data = append(data, statsCsvFields(result.Summarize(row.ThroughputSummary))...)
+		data = append(data, statsCsvFields(result.Summarize(row.LatencySummary))...)
 		if err := archive.Write(data); err != nil {
 			return fmt.Errorf("failed to write archive to file")
 		}
 	}
 	return nil
 }
+
+// WriteMarkdownResult will write the results as markdown tables to the local filesystem.
+// When baseline is not nil each row carries the %diff against the matching baseline test.
+func WriteMarkdownResult(r result.ScenarioResults, baseline *result.ScenarioResults) error {
+	d := time.Now().Unix()
+	fn := fmt.Sprintf("result-%d.md", d)
+	fp, err := os.Create(fn)
+	if err != nil {
+		return fmt.Errorf("failed to open markdown file")
+	}
+	defer func() {
+		if err := fp.Close(); err != nil {
+			logging.Warnf("Error closing markdown file: %v", err)
+		}
+	}()
+	if err := result.RenderMarkdown(fp, r, baseline); err != nil {
+		return fmt.Errorf("failed to write markdown to file: %v", err)
+	}
+	logging.Infof("📝 Markdown summary written to %s", fn)
+	return nil
+}
+
+// ReadJSONResult loads a JSON result, as written by WriteJSONResult, back into ScenarioResults.
+func ReadJSONResult(fn string) (result.ScenarioResults, error) {
+	var sr result.ScenarioResults
+	buf, err := os.ReadFile(fn)
+	if err != nil {
+		return sr, err
+	}
+	var docs []Doc
+	if err := json.Unmarshal(buf, &docs); err != nil {
+		return sr, fmt.Errorf("in file %q: %v", fn, err)
+	}
+	for i, d := range docs {
+		if i == 0 {
+			sr.UUID = d.UUID
+			sr.Timestamp = d.Timestamp
+			sr.Version = d.ToolVersion
+			sr.GitCommit = d.ToolGitCommit
+			sr.Metadata = d.Metadata
+		}
+		if d.Virt {
+			sr.Virt = true
+		}
+		sr.Results = append(sr.Results, docToData(d))
+	}
+	return sr, nil
+}
+
+// docToData maps an archived document back to a result.
+func docToData(d Doc) result.Data {
+	r := result.Data{
+		Driver:             d.Driver,
+		Metric:             d.TputMetric,
+		SameNode:           d.Local,
+		HostNetwork:        d.HostNetwork,
+		ClientNodeInfo:     d.ClientNodeInfo,
+		ServerNodeInfo:     d.ServerNodeInfo,
+		StartTime:          d.Timestamp,
+		EndTime:            d.Timestamp,
+		Service:            d.Service,
+		AcrossAZ:           d.AcrossAZ,
+		ThroughputSummary:  []float64{d.Throughput},
+		LatencySummary:     []float64{d.Latency},
+		LossSummary:        []float64{d.UDPLossPercent},
+		RetransmitSummary:  []float64{d.TCPRetransmit},
+		ClientMetrics:      d.ClientNodeCPU,
+		ServerMetrics:      d.ServerNodeCPU,
+		ClientCPUCollected: d.ClientCPUCollected,
+		ServerCPUCollected: d.ServerCPUCollected,
+		ClientPodCPU:       metrics.PodValues{Results: d.ClientPodCPU},
+		ServerPodCPU:       metrics.PodValues{Results: d.ServerPodCPU},
+		ClientPodMem:       metrics.PodValues{MemResults: d.ClientPodMem},
+		ServerPodMem:       metrics.PodValues{MemResults: d.ServerPodMem},
+		ExternalServer:     d.ExternalServer,
+		UdnInfo:            d.UdnInfo,
+		BridgeInfo:         d.BridgeInfo,
+		SriovInfo:          d.SriovInfo,
+		MacvlanInfo:        d.MacvlanInfo,
+		LocalnetInfo:       d.LocalnetInfo,
+		Virt:               d.Virt,
+		Path:               d.Path,
+		RuntimeClass:       d.RuntimeClass,
+		VMSpec:             d.VMSpec,
+		QOSClass:           d.QOSClass,
+		Status:             d.Status,
+		Reason:             d.Reason,
+	}
+	// Results archived before the raw samples were recorded only carry the averages.
+	if len(d.ThroughputSamples) > 0 {
+		r.ThroughputSummary = d.ThroughputSamples
+	}
+	if len(d.LatencySamples) > 0 {
+		r.LatencySummary = d.LatencySamples
+	}
+	r.LatencyAvgSummary = d.LatencyAvgSamples
+	r.Latency50Summary = d.Latency50Samples
+	if len(d.LossSamples) > 0 {
+		r.LossSummary = d.LossSamples
+	}
+	if len(d.RetransmitSamples) > 0 {
+		r.RetransmitSummary = d.RetransmitSamples
+	}
+	r.WarmupThroughputSummary = d.WarmupThroughput
+	r.WarmupLatencySummary = d.WarmupLatency
+	r.Endpoints = d.Endpoints
+	r.EndpointThroughputSummary = d.EndpointThroughput
+	r.Incast = d.Incast
+	r.Fanout = d.Fanout
+	r.Pairs = d.Pairs
+	r.Spread = d.Spread
+	r.FairnessSummary = d.FairnessSamples
+	r.Migrate = d.Migrate
+	r.MigrateAfter = d.MigrateAfter
+	r.Migration = d.Migration
+	r.Parallelism = d.Parallelism
+	r.Profile = d.Profile
+	r.Duration = d.Duration
+	r.Samples = d.Samples
+	r.MessageSize = d.Messagesize
+	r.Burst = d.Burst
+	r.Config.Service = d.Service
+	r.Config.Metric = d.TputMetric
+	r.Config.AcrossAZ = d.AcrossAZ
+	return r
+}
@@ -1,9 +1,15 @@
 package archive
 
 import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/config"
 	result "github.com/cloud-bulldozer/k8s-netperf/pkg/results"
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/sample"
 )
 
 func TestBuildDocsMapsCPUCollectionFlags(t *testing.T) {
@@ -38,3 +44,88 @@ func TestBuildDocsMapsCPUCollectionFlags(t *testing.T) {
 		t.Fatal("Doc.ServerCPUCollected = false, want true")
 	}
 }
+
+func TestReadJSONResultRoundTrip(t *testing.T) {
+	sr := result.ScenarioResults{
+		Version: "v1.0.0",
+		Results: []result.Data{
+			{
+				Driver:            "netperf",
+				Metric:            "OP/s",
+				ThroughputSummary: []float64{100, 110, 105},
+				LatencySummary:    []float64{20, 22, 21},
+				LatencyAvgSummary: []float64{10, 11, 12},
+				Latency50Summary:  []float64{9, 9, 9},
+				LossSummary:       []float64{0, 0, 0},
+				RetransmitSummary: []float64{1, 2, 3},
+			},
+		},
+	}
+	sr.Results[0].Profile = "TCP_RR"
+	sr.Results[0].Samples = 3
+	sr.Results[0].MessageSize = 1024
+	sr.Results[0].VMSpec = &config.VMSpec{Sockets: 1, Cores: 4, Threads: 1, Memory: "8Gi", DedicatedCPUs: true, Hugepages: "1Gi", MultiQueue: true, MachineType: "pc-q35-rhel9.4.0"}
+	sr.Results[0].Virt = true
+	sr.Results[0].Path = result.PathVMToPod
+	sr.Results[0].QOSClass = "Guaranteed"
+	sr.Results[0].RuntimeClass = "kata"
+	sr.Results[0].Migrate = config.MigrateServer
+	sr.Results[0].MigrateAfter = 10
+	sr.Results[0].Migration = &result.Migration{VMI: config.MigrateServer, SourceNode: "worker-0", TargetNode: "worker-2", Start: 10.1, End: 14.3, Downtime: 0.8,
+		Throughput: []sample.Point{{Offset: 0.5, Value: 9000}}, RTT: []sample.Point{{Offset: 0.2, Value: 0.3}}, Lost: []float64{12.4}}
+	sr.Kernel = "6.1.0"
+
+	docs, err := BuildDocs(sr, "test-uuid")
+	if err != nil {
+		t.Fatalf("BuildDocs returned unexpected error: %v", err)
+	}
+	buf, err := json.Marshal(docs)
+	if err != nil {
+		t.Fatalf("json.Marshal returned unexpected error: %v", err)
+	}
+	fn := filepath.Join(t.TempDir(), "result.json")
+	if err := os.WriteFile(fn, buf, 0o644); err != nil {
+		t.Fatalf("os.WriteFile returned unexpected error: %v", err)
+	}
+
+	got, err := ReadJSONResult(fn)
+	if err != nil {
+		t.Fatalf("ReadJSONResult returned unexpected error: %v", err)
+	}
+	if got.UUID != "test-uuid" || got.Version != "v1.0.0" || got.Kernel != "6.1.0" {
+		t.Fatalf("ReadJSONResult returned uuid=%q version=%q kernel=%q", got.UUID, got.Version, got.Kernel)
+	}
+	if len(got.Results) != 1 {
+		t.Fatalf("ReadJSONResult returned %d results, want 1", len(got.Results))
+	}
+	r := got.Results[0]
+	if result.TestID(r) != result.TestID(sr.Results[0]) {
+		t.Fatalf("ReadJSONResult returned test %q, want %q", result.TestID(r), result.TestID(sr.Results[0]))
+	}
+	if !reflect.DeepEqual(r.ThroughputSummary, sr.Results[0].ThroughputSummary) ||
+		!reflect.DeepEqual(r.LatencyAvgSummary, sr.Results[0].LatencyAvgSummary) ||
+		!reflect.DeepEqual(r.RetransmitSummary, sr.Results[0].RetransmitSummary) {
+		t.Fatalf("ReadJSONResult did not restore the raw samples: %+v", r)
+	}
+	if !reflect.DeepEqual(r.VMSpec, sr.Results[0].VMSpec) {
+		t.Fatalf("ReadJSONResult returned VM spec %+v, want %+v", r.VMSpec, sr.Results[0].VMSpec)
+	}
+	if !r.Virt || r.Path != result.PathVMToPod {
+		t.Fatalf("ReadJSONResult returned virt %t path %q, want true and %q", r.Virt, r.Path, result.PathVMToPod)
+	}
+	if r.QOSClass != "Guaranteed" || r.RuntimeClass != "kata" {
+		t.Fatalf("ReadJSONResult returned QoS class %q runtime class %q, want Guaranteed and kata", r.QOSClass, r.RuntimeClass)
+	}
+	if r.Migrate != config.MigrateServer || r.MigrateAfter != 10 || !reflect.DeepEqual(r.Migration, sr.Results[0].Migration) {
+		t.Fatalf("ReadJSONResult returned migration %s after %d %+v, want %+v", r.Migrate, r.MigrateAfter, r.Migration, sr.Results[0].Migration)
+	}
+
+	// Re-indexing keeps the timestamp of the original documents.
+	redocs, err := BuildDocs(got, got.UUID)
+	if err != nil {
+		t.Fatalf("BuildDocs returned unexpected error: %v", err)
+	}
+	if ts := redocs[0].(Doc).Timestamp; !ts.Equal(docs[0].(Doc).Timestamp) {
+		t.Fatalf("BuildDocs returned timestamp %s, want %s", ts, docs[0].(Doc).Timestamp)
+	}
+}
@@ -0,0 +1,69 @@
+package archive
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/logging"
+	result "github.com/cloud-bulldozer/k8s-netperf/pkg/results"
+)
+
+const checkpointFile = "results.json"
+
+// CheckpointDir returns the directory the checkpoint of the run with the given UUID is kept in.
+func CheckpointDir(dir, uuid string) string {
+	return filepath.Join(dir, uuid)
+}
+
+// SaveCheckpoint persists the results of the run with the given UUID, so that an
+// interrupted run can be resumed. The checkpoint is replaced atomically.
+func SaveCheckpoint(dir, uuid string, results []result.Data) error {
+	runDir := CheckpointDir(dir, uuid)
+	if err := os.MkdirAll(runDir, 0o755); err != nil {
+		return fmt.Errorf("unable to create run directory: %v", err)
+	}
+	data, err := json.Marshal(results)
+	if err != nil {
+		return fmt.Errorf("unable to marshal checkpoint: %v", err)
+	}
+	tmp, err := os.CreateTemp(runDir, checkpointFile+".*")
+	if err != nil {
+		return fmt.Errorf("unable to write checkpoint: %v", err)
+	}
+	// The temporary file is gone once renamed, it is only left behind on errors.
+	defer func() {
+		if err := os.Remove(tmp.Name()); err != nil && !os.IsNotExist(err) {
+			logging.Warnf("Error removing temporary checkpoint file: %v", err)
+		}
+	}()
+	if _, err := tmp.Write(data); err != nil {
+		if cerr := tmp.Close(); cerr != nil {
+			logging.Warnf("Error closing temporary checkpoint file: %v", cerr)
+		}
+		return fmt.Errorf("unable to write checkpoint: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("unable to write checkpoint: %v", err)
+	}
+	return os.Rename(tmp.Name(), filepath.Join(runDir, checkpointFile))
+}
+
+// LoadCheckpoint reads the results persisted by SaveCheckpoint for the run with the given UUID.
+func LoadCheckpoint(dir, uuid string) ([]result.Data, error) {
+	data, err := os.ReadFile(filepath.Join(CheckpointDir(dir, uuid), checkpointFile))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read checkpoint of run %s: %v", uuid, err)
+	}
+	var results []result.Data
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, fmt.Errorf("unable to parse checkpoint of run %s: %v", uuid, err)
+	}
+	return results, nil
+}
+
+// RemoveCheckpoint deletes the run directory of the run with the given UUID.
+func RemoveCheckpoint(dir, uuid string) error {
+	return os.RemoveAll(CheckpointDir(dir, uuid))
+}
@@ -0,0 +1,55 @@
+package archive
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/config"
+	result "github.com/cloud-bulldozer/k8s-netperf/pkg/results"
+)
+
+func TestCheckpointRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	want := []result.Data{
+		{
+			Config:            config.Config{Profile: "TCP_STREAM", Parallelism: 1, MessageSize: 1024, Duration: 10, Samples: 2},
+			Driver:            "netperf",
+			Metric:            "Mb/s",
+			StartTime:         start,
+			EndTime:           start.Add(20 * time.Second),
+			ThroughputSummary: []float64{100, 110},
+			Status:            result.StatusCompleted,
+		},
+		{
+			Config: config.Config{Profile: "UDP_STREAM", Parallelism: 1, MessageSize: 1024, Duration: 10, Samples: 2},
+			Driver: "netperf",
+			Status: result.StatusFailed,
+			Reason: "netperf UDP_STREAM test was unsuccessful after retry",
+		},
+	}
+	if err := SaveCheckpoint(dir, "run-1", want[:1]); err != nil {
+		t.Fatalf("SaveCheckpoint: %v", err)
+	}
+	if err := SaveCheckpoint(dir, "run-1", want); err != nil {
+		t.Fatalf("SaveCheckpoint: %v", err)
+	}
+	got, err := LoadCheckpoint(dir, "run-1")
+	if err != nil {
+		t.Fatalf("LoadCheckpoint: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("checkpoint mismatch:\n got %+v\nwant %+v", got, want)
+	}
+	if _, err := LoadCheckpoint(dir, "run-2"); err == nil {
+		t.Fatalf("expected an error loading a missing checkpoint")
+	}
+	if err := RemoveCheckpoint(dir, "run-1"); err != nil {
+		t.Fatalf("RemoveCheckpoint: %v", err)
+	}
+	if _, err := os.Stat(CheckpointDir(dir, "run-1")); !os.IsNotExist(err) {
+		t.Fatalf("expected the run directory to be removed, got %v", err)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"regexp"
+	"strconv"
+	"strings"
 
 	kubevirtv1 "github.com/cloud-bulldozer/k8s-netperf/pkg/kubevirt/client-go/clientset/versioned/typed/core/v1"
 	"github.com/melbahja/goph"
@@ -35,6 +37,229 @@ type Config struct {
 	Service         bool   `default:"false" yaml:"service,omitempty"`
 	Metric          string
 	AcrossAZ        bool
+	// AutoSamples is set with `samples: auto`, sampling continues from MinSamples until the
+	// 95% confidence interval is within TargetCI percent of the mean, or MaxSamples is reached.
+	AutoSamples bool    `yaml:"-"`
+	MinSamples  int     `yaml:"minSamples,omitempty"`
+	MaxSamples  int     `yaml:"maxSamples,omitempty"`
+	TargetCI    float64 `yaml:"targetCI,omitempty"`
+	Convergence string  `yaml:"convergence,omitempty"`
+	// Warmup is either a number of samples ("2") or a duration in seconds ("30s") run
+	// before the measured samples. Warm-up results are recorded but not part of the statistics.
+	Warmup         string `yaml:"warmup,omitempty"`
+	WarmupSamples  int    `yaml:"-"`
+	WarmupDuration int    `yaml:"-"`
+	// Cooldown is the time in seconds to sleep between samples
+	Cooldown int `yaml:"cooldown,omitempty"`
+	// Incast runs the test from Incast client pods, each on its own node, towards one server.
+	// Fanout runs the test from one client pod towards Fanout server pods, each on its own node.
+	Incast int `yaml:"incast,omitempty"`
+	Fanout int `yaml:"fanout,omitempty"`
+	// Pairs runs the test on Pairs client/server pod pairs at once, between the client
+	// and server nodes, or with Spread, each pod on its own node.
+	Pairs  int  `yaml:"pairs,omitempty"`
+	Spread bool `yaml:"spread,omitempty"`
+	// Migrate live-migrates the server or client VMI MigrateAfter seconds into the test,
+	// to measure the disruption. MigrateAfter defaults to a third of the duration.
+	Migrate      string `yaml:"migrate,omitempty"`
+	MigrateAfter int    `yaml:"migrateAfter,omitempty"`
+	// PortOffset shifts the data ports of the test, so that the concurrent tests of an
+	// incast or fan-out do not collide on the server or the client.
+	PortOffset int `yaml:"-"`
+}
+
+// Topologies of a test
+const (
+	// TopologyIncast is N clients towards one server
+	TopologyIncast = "incast"
+	// TopologyFanout is one client towards M servers
+	TopologyFanout = "fanout"
+	// TopologyPairs is N clients, each towards its own server
+	TopologyPairs = "pairs"
+)
+
+// Topology returns the topology of the test, empty for a single client and server.
+func (c Config) Topology() string {
+	switch {
+	case c.Incast > 0:
+		return TopologyIncast
+	case c.Fanout > 0:
+		return TopologyFanout
+	case c.Pairs > 0:
+		return TopologyPairs
+	}
+	return ""
+}
+
+// Endpoints returns the number of clients, servers or pairs of the topology of the test.
+func (c Config) Endpoints() int {
+	switch {
+	case c.Incast > 0:
+		return c.Incast
+	case c.Fanout > 0:
+		return c.Fanout
+	case c.Pairs > 0:
+		return c.Pairs
+	}
+	return 1
+}
+
+// VMIs a migration test migrates
+const (
+	MigrateServer = "server"
+	MigrateClient = "client"
+)
+
+// Defaults for adaptive sampling
+const (
+	defaultMinSamples = 3
+	defaultMaxSamples = 10
+	defaultTargetCI   = 2.0
+	// ConvergeThroughput converges on the throughput samples
+	ConvergeThroughput = "throughput"
+	// ConvergeLatency converges on the 99%tile latency samples
+	ConvergeLatency = "latency"
+)
+
+// UnmarshalYAML accepts `samples: auto` in addition to a fixed number of samples.
+func (c *Config) UnmarshalYAML(value *yaml.Node) error {
+	type plain Config
+	node := *value
+	if node.Kind == yaml.MappingNode {
+		node.Content = make([]*yaml.Node, len(value.Content))
+		copy(node.Content, value.Content)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == "samples" && strings.EqualFold(node.Content[i+1].Value, "auto") {
+				c.AutoSamples = true
+				node.Content[i+1] = &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: "0"}
+			}
+		}
+	}
+	if err := node.Decode((*plain)(c)); err != nil {
+		return err
+	}
+	if c.Warmup != "" {
+		var err error
+		c.WarmupSamples, c.WarmupDuration, err = parseWarmup(c.Warmup)
+		if err != nil {
+			return err
+		}
+	}
+	if c.Migrate != "" && c.MigrateAfter == 0 {
+		c.MigrateAfter = c.Duration / 3
+	}
+	if c.AutoSamples {
+		if c.MinSamples == 0 {
+			c.MinSamples = defaultMinSamples
+		}
+		if c.MaxSamples == 0 {
+			c.MaxSamples = defaultMaxSamples
+		}
+		if c.TargetCI == 0 {
+			c.TargetCI = defaultTargetCI
+		}
+		if c.Convergence == "" {
+			c.Convergence = ConvergeThroughput
+		}
+		c.Samples = c.MinSamples
+	}
+	return nil
+}
+
+// VMSpec is the sizing of the VMs.
+type VMSpec struct {
+	Sockets uint32 `json:"sockets"`
+	Cores   uint32 `json:"cores"`
+	Threads uint32 `json:"threads"`
+	// Memory of the VM, e.g. 4096Mi
+	Memory string `json:"memory"`
+	// DedicatedCPUs pins the vCPUs to dedicated host CPUs.
+	DedicatedCPUs bool `json:"dedicatedCpus"`
+	// NUMA passes the NUMA topology of the host CPUs through to the guest.
+	NUMA bool `json:"numa"`
+	// Hugepages is the page size backing the memory, 2Mi or 1Gi, none when empty.
+	Hugepages string `json:"hugepages,omitempty"`
+	// MultiQueue enables virtio-net multiqueue.
+	MultiQueue  bool   `json:"multiQueue"`
+	MachineType string `json:"machineType,omitempty"`
+	// QOSClass of the virt-launcher pod, only known once the VMI runs.
+	QOSClass string `json:"qosClass,omitempty"`
+}
+
+// imageKey is the key of the image section of the configuration file, which is not a test.
+const imageKey = "image"
+
+// Image is the container image of the k8s-netperf pods, and how it is pulled.
+type Image struct {
+	// Name of the image, e.g. quay.io/cloud-bulldozer/k8s-netperf:latest
+	Name string `yaml:"name,omitempty"`
+	// Mirror is the registry to pull the image from instead of the registry of its name.
+	Mirror string `yaml:"mirror,omitempty"`
+	// Digest pins the image, e.g. sha256:0123...
+	Digest string `yaml:"digest,omitempty"`
+	// PullPolicy is Always, IfNotPresent or Never.
+	PullPolicy string `yaml:"pullPolicy,omitempty"`
+	// PullSecrets are the secrets to pull the image with, as namespace/name.
+	PullSecrets []string `yaml:"pullSecrets,omitempty"`
+}
+
+// UnmarshalYAML accepts `image: <name>` in addition to the image section.
+func (i *Image) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		i.Name = value.Value
+		return nil
+	}
+	type plain Image
+	return value.Decode((*plain)(i))
+}
+
+// resourcesKey is the key of the resources section of the configuration file, which is not a test.
+const resourcesKey = "resources"
+
+// Resources are the resources of the containers of the k8s-netperf pods, and how the pods
+// are placed.
+type Resources struct {
+	// CPU and Memory are the requests of each container, e.g. 2 and 1Gi.
+	CPU    string `yaml:"cpu,omitempty"`
+	Memory string `yaml:"memory,omitempty"`
+	// CPULimit and MemoryLimit are the limits of each container, none when empty.
+	CPULimit    string `yaml:"cpuLimit,omitempty"`
+	MemoryLimit string `yaml:"memoryLimit,omitempty"`
+	// Guaranteed sets the limits to the requests, so that the pods have the Guaranteed QoS
+	// class, and requires an integer number of CPUs, pinned by the static CPU Manager policy.
+	Guaranteed bool `yaml:"guaranteed,omitempty"`
+	// Hugepages is the amount of hugepages of each container, e.g. 1Gi.
+	Hugepages string `yaml:"hugepages,omitempty"`
+	// HugepageSize is the size of the hugepages, 2Mi or 1Gi.
+	HugepageSize string `yaml:"hugepageSize,omitempty"`
+	// RuntimeClassName is the runtime class of the pods, other than the host network pods.
+	RuntimeClassName string `yaml:"runtimeClassName,omitempty"`
+	// SchedulerName is the scheduler of the pods, e.g. the topology-aware scheduler of the
+	// NUMA Resources Operator, which places them on a node whose NUMA zones fit them.
+	SchedulerName string `yaml:"schedulerName,omitempty"`
+}
+
+// schedulingKey is the key of the scheduling section of the configuration file, which is not a test.
+const schedulingKey = "scheduling"
+
+// Scheduling is where the k8s-netperf pods and VMs may run, besides the worker nodes.
+type Scheduling struct {
+	// Tolerations of the taints of the nodes, e.g. of a dedicated pool.
+	Tolerations []Toleration `yaml:"tolerations,omitempty"`
+	// NodeSelectorTerms are label selectors of the nodes which are eligible besides the worker
+	// nodes, e.g. node-role.kubernetes.io/gpu=.
+	NodeSelectorTerms []string `yaml:"nodeSelectorTerms,omitempty"`
+	// PriorityClassName is the priority class of the pods and VMs.
+	PriorityClassName string `yaml:"priorityClassName,omitempty"`
+}
+
+// Toleration tolerates a taint of the nodes. The operator is Exists without a value, Equal
+// otherwise, unless set.
+type Toleration struct {
+	Key      string `yaml:"key,omitempty"`
+	Operator string `yaml:"operator,omitempty"`
+	Value    string `yaml:"value,omitempty"`
+	Effect   string `yaml:"effect,omitempty"`
 }
 
 // PerfScenarios describes the different scenarios
@@ -49,9 +274,13 @@ type PerfScenarios struct {
 	Pod                   bool
 	VM                    bool
 	VMImage               string
+	VMTools               string
+	VMToolsConfigMap      string
 	VMHost                string
 	VMName                string
 	UseVirtctl            bool
+	VMAccess              string
+	VMSSHKey              []byte
 	Udn                   bool
 	Cudn                  bool
 	UdnPluginBinding      string
@@ -65,10 +294,13 @@ type PerfScenarios struct {
 	LocalnetServerNetwork string
 	LocalnetClientNetwork string
 	IbWriteBwParams       string
-	Sockets               uint32
-	Cores                 uint32
-	Threads               uint32
+	VMSpec                VMSpec
+	VMEffectiveSpec       *VMSpec
 	RequestedDrivers      []string
+	ClientNode            string
+	ClientNodeSelector    string
+	ServerNode            string
+	ServerNodeSelector    string
 	ServerNodeInfo        metrics.NodeInfo
 	ClientNodeInfo        metrics.NodeInfo
 	Client                apiv1.PodList
@@ -76,9 +308,18 @@ type PerfScenarios struct {
 	Server                apiv1.PodList
 	VMServer              apiv1.PodList
 	ClientAcross          apiv1.PodList
+	IncastClients         apiv1.PodList
+	FanoutServers         apiv1.PodList
+	PairClients           apiv1.PodList
+	PairServers           apiv1.PodList
+	SpreadPairClients     apiv1.PodList
+	SpreadPairServers     apiv1.PodList
 	VMClientAcross        apiv1.PodList
 	ClientHost            apiv1.PodList
 	ServerHost            apiv1.PodList
+	SandboxRuntimeClass   string
+	SandboxClient         apiv1.PodList
+	SandboxServer         apiv1.PodList
 	NetperfService        *apiv1.Service
 	IperfService          *apiv1.Service
 	UperfService          *apiv1.Service
@@ -109,6 +350,24 @@ type LocalnetNetworkConfig struct {
 // Tests we will support in k8s-netperf
 const validTests = "tcp_stream_lat|tcp_stream|udp_stream|tcp_rr|udp_rr|tcp_crr|udp_crr|sctp_stream|sctp_rr|sctp_crr"
 
+// parseWarmup accepts a number of warm-up samples, or a warm-up duration suffixed with "s".
+// returns the number of samples and the duration in seconds, only one of them is set.
+func parseWarmup(w string) (int, int, error) {
+	w = strings.TrimSpace(w)
+	if strings.HasSuffix(w, "s") {
+		d, err := strconv.Atoi(strings.TrimSuffix(w, "s"))
+		if err != nil || d < 0 {
+			return 0, 0, fmt.Errorf("invalid warmup duration %q", w)
+		}
+		return 0, d, nil
+	}
+	n, err := strconv.Atoi(w)
+	if err != nil || n < 0 {
+		return 0, 0, fmt.Errorf("invalid warmup %q, expected a number of samples or seconds (e.g. 30s)", w)
+	}
+	return n, 0, nil
+}
+
 func validConfig(cfg Config) (bool, error) {
 	preEval := regexp.MustCompile("(?i)" + validTests)
 	p := preEval.MatchString(cfg.Profile)
@@ -121,40 +380,117 @@ func validConfig(cfg Config) (bool, error) {
 	if cfg.Samples < 1 {
 		return false, fmt.Errorf("samples must be > 0")
 	}
+	if cfg.Cooldown < 0 {
+		return false, fmt.Errorf("cooldown must be >= 0")
+	}
+	if cfg.AutoSamples {
+		if cfg.MinSamples < 2 {
+			return false, fmt.Errorf("minSamples must be > 1 with samples: auto")
+		}
+		if cfg.MaxSamples < cfg.MinSamples {
+			return false, fmt.Errorf("maxSamples must be >= minSamples")
+		}
+		if cfg.TargetCI <= 0 {
+			return false, fmt.Errorf("targetCI must be > 0")
+		}
+		if cfg.Convergence != ConvergeThroughput && cfg.Convergence != ConvergeLatency {
+			return false, fmt.Errorf("convergence must be %s or %s", ConvergeThroughput, ConvergeLatency)
+		}
+	}
 	if cfg.MessageSize < 1 {
 		return false, fmt.Errorf("messagesize must be > 0")
 	}
 	if cfg.Parallelism < 1 {
 		return false, fmt.Errorf("parallelism must be > 0")
 	}
+	if cfg.Incast < 0 || cfg.Incast == 1 {
+		return false, fmt.Errorf("incast must be > 1")
+	}
+	if cfg.Fanout < 0 || cfg.Fanout == 1 {
+		return false, fmt.Errorf("fanout must be > 1")
+	}
+	if cfg.Pairs < 0 || cfg.Pairs == 1 {
+		return false, fmt.Errorf("pairs must be > 1")
+	}
+	topologies := 0
+	for _, n := range []int{cfg.Incast, cfg.Fanout, cfg.Pairs} {
+		if n > 0 {
+			topologies++
+		}
+	}
+	if topologies > 1 {
+		return false, fmt.Errorf("incast, fanout and pairs are mutually exclusive")
+	}
+	if cfg.Spread && cfg.Pairs == 0 {
+		return false, fmt.Errorf("spread requires pairs")
+	}
+	if cfg.Migrate != "" {
+		if cfg.Migrate != MigrateServer && cfg.Migrate != MigrateClient {
+			return false, fmt.Errorf("migrate must be %s or %s", MigrateServer, MigrateClient)
+		}
+		if cfg.MigrateAfter < 1 || cfg.MigrateAfter >= cfg.Duration {
+			return false, fmt.Errorf("migrateAfter must be > 0 and < duration")
+		}
+		if cfg.Samples != 1 || cfg.AutoSamples || cfg.Parallelism != 1 {
+			return false, fmt.Errorf("migrate tests run one sample of a single stream, samples and parallelism must be 1")
+		}
+		if cfg.Service || topologies > 0 {
+			return false, fmt.Errorf("migrate tests run between the client and server VMIs, without a service, incast, fanout or pairs")
+		}
+	}
 	return true, nil
 }
 
+// reservedKeys are the top-level keys of the configuration file which hold settings, not tests.
+var reservedKeys = []string{imageKey, resourcesKey, schedulingKey}
+
+// File is the netperf configuration file, the tests and the settings of its sections.
+type File struct {
+	Tests      []Config   `yaml:"-"`
+	Image      Image      `yaml:"image"`
+	Resources  Resources  `yaml:"resources"`
+	Scheduling Scheduling `yaml:"scheduling"`
+}
+
+// ParseConfig reads the netperf configuration file, in either the v1 or the v2 format, with
+// the image, resources and scheduling sections:
+//
+//	image:
+//	  name: quay.io/cloud-bulldozer/k8s-netperf:latest
+//	  pullPolicy: IfNotPresent
+//	resources:
+//	  cpu: 2
+//	  memory: 1Gi
+//	scheduling:
+//	  tolerations:
+//	    - key: dedicated
+//	      effect: NoSchedule
+//	tests:
+//	  - TCPStream:
+//	    ...
+func ParseConfig(fn string) (File, error) {
+	log.Infof("📒 Reading %s file. ", fn)
+	c, f, err := readConf(fn)
+	if err != nil {
+		return f, err
+	}
+	if f.Tests, err = parseTests(fn, c); err != nil {
+		log.Debug("Using ConfigV2 Method")
+		f.Tests, err = parseV2Tests(fn, c)
+	}
+	return f, err
+}
+
 // ParseConf will read in the netperf configuration file which
 // describes which tests to run
 // Returns Config struct
 func ParseConf(fn string) ([]Config, error) {
 	log.Infof("📒 Reading %s file. ", fn)
-	buf, err := os.ReadFile(fn)
+	c, _, err := readConf(fn)
 	if err != nil {
 		return nil, err
 	}
-	c := make(map[string]Config)
-	err = yaml.Unmarshal(buf, &c)
-	if err != nil {
-		return nil, fmt.Errorf("in file %q: %v", fn, err)
-	}
-	// Ignore the key
-	// Pull out the specific tests
-	var tests []Config
-	for _, value := range c {
-		ok, err := validConfig(value)
-		if !ok {
-			return nil, err
-		}
-		tests = append(tests, value)
-	}
-	return tests, nil
+	return parseTests(fn, c)
 }
 
 // ParseV2Conf will read in the netperf configuration file which
@@ -162,24 +498,74 @@ func ParseConf(fn string) ([]Config, error) {
 // Returns Config struct
 func ParseV2Conf(fn string) ([]Config, error) {
 	log.Infof("📒 Reading %s file - using ConfigV2 Method. ", fn)
-	buf, err := os.ReadFile(fn)
+	c, _, err := readConf(fn)
 	if err != nil {
 		return nil, err
 	}
-	c := make(map[string][]Config)
-	// New YAML structure :
-	// tests :
-	//   - Test_name :
-	//     profile: <xyz> ...
-	err = yaml.Unmarshal(buf, &c)
+	return parseV2Tests(fn, c)
+}
+
+// readConf reads the configuration file, and returns its top-level keys other than the
+// sections, and the settings of the sections. A test named after a section is an error,
+// it would be taken for the section.
+func readConf(fn string) (map[string]yaml.Node, File, error) {
+	var f File
+	buf, err := os.ReadFile(fn)
 	if err != nil {
-		return nil, fmt.Errorf("in file %q: %v", fn, err)
+		return nil, f, err
+	}
+	c := make(map[string]yaml.Node)
+	if err := yaml.Unmarshal(buf, &c); err != nil {
+		return nil, f, fmt.Errorf("in file %q: %v", fn, err)
+	}
+	for _, key := range reservedKeys {
+		node, ok := c[key]
+		if !ok {
+			continue
+		}
+		var test map[string]yaml.Node
+		if node.Decode(&test) == nil {
+			if _, ok := test["profile"]; ok {
+				return nil, f, fmt.Errorf("in file %q: %s is reserved for the %s section, rename the test", fn, key, key)
+			}
+		}
+		delete(c, key)
+	}
+	if err := yaml.Unmarshal(buf, &f); err != nil {
+		return nil, f, fmt.Errorf("in file %q: %v", fn, err)
 	}
+	return c, f, nil
+}
 
-	// Ignore the key
-	// Pull out the specific tests
+// parseTests returns the tests of the v1 format, one per top-level key.
+func parseTests(fn string, c map[string]yaml.Node) ([]Config, error) {
 	var tests []Config
-	for _, cf := range c {
+	for _, node := range c {
+		var value Config
+		if err := node.Decode(&value); err != nil {
+			return nil, fmt.Errorf("in file %q: %v", fn, err)
+		}
+		ok, err := validConfig(value)
+		if !ok {
+			return nil, err
+		}
+		tests = append(tests, value)
+	}
+	return tests, nil
+}
+
+// parseV2Tests returns the tests of the v2 format, listed under a top-level key:
+//
+//	tests :
+//	  - Test_name :
+//	    profile: <xyz> ...
+func parseV2Tests(fn string, c map[string]yaml.Node) ([]Config, error) {
+	var tests []Config
+	for _, node := range c {
+		var cf []Config
+		if err := node.Decode(&cf); err != nil {
+			return nil, fmt.Errorf("in file %q: %v", fn, err)
+		}
 		for _, cfg := range cf {
 			ok, err := validConfig(cfg)
 			if !ok {
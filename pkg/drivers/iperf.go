@@ -60,7 +60,7 @@ func (i *iperf3) Run(c *kubernetes.Clientset,
 			clientIp = udnIp
 		}
 	} else if perf.Cudn {
-		if udnIp, _ := k8s.ExtractUdnIp(pod, k8s.CudnName); udnIp != "" {
+		if udnIp, _ := k8s.ExtractUdnIp(pod, k8s.CudnName()); udnIp != "" {
 			clientIp = udnIp
 		}
 	} else if perf.BridgeNetwork != "" {
@@ -100,7 +100,6 @@ func (i *iperf3) Run(c *kubernetes.Clientset,
 	// Vm mode
 	if virt {
 		retry := 10
-		present := false
 		createdClient := false
 		var err error
 		var vmClient config.VMExecutor
@@ -114,23 +113,6 @@ func (i *iperf3) Run(c *kubernetes.Clientset,
 			}
 			createdClient = true
 		}
-		for i := 0; i <= retry; i++ {
-			log.Debug("⏰ Waiting for iperf3 to be present on VM")
-			_, err = vmClient.Run("until iperf3 -h; do sleep 30; done")
-			if err == nil {
-				present = true
-				break
-			}
-			time.Sleep(10 * time.Second)
-		}
-		if !present {
-			if createdClient {
-				if err := vmClient.Close(); err != nil {
-					log.Warnf("Error closing VM client: %v", err)
-				}
-			}
-			return stdout, fmt.Errorf("iperf3 binary is not present on the VM")
-		}
 		var stdout []byte
 		ran := false
 		for i := 0; i <= retry; i++ {
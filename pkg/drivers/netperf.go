@@ -39,7 +39,7 @@ func (n *netperf) Run(c *kubernetes.Clientset, rc rest.Config, nc config.Config,
 			clientIp = udnIp
 		}
 	} else if perf.Cudn {
-		if udnIp, _ := k8s.ExtractUdnIp(pod, k8s.CudnName); udnIp != "" {
+		if udnIp, _ := k8s.ExtractUdnIp(pod, k8s.CudnName()); udnIp != "" {
 			clientIp = udnIp
 		}
 	} else if perf.BridgeNetwork != "" {
@@ -49,7 +49,7 @@ func (n *netperf) Run(c *kubernetes.Clientset, rc rest.Config, nc config.Config,
 	}
 	log.Debugf("🔥 Client (%s,%s) starting netperf against server: %s", pod.Name, clientIp, serverIP)
 	config.Show(nc, n.driverName)
-	cmd := []string{superNetperf, strconv.Itoa(nc.Parallelism), strconv.Itoa(k8s.NetperfServerDataPort), "-H",
+	cmd := []string{superNetperf, strconv.Itoa(nc.Parallelism), strconv.Itoa(k8s.NetperfServerDataPort + nc.PortOffset), "-H",
 		serverIP, "-l",
 		fmt.Sprint(nc.Duration),
 		"-t", nc.Profile,
@@ -78,7 +78,6 @@ func (n *netperf) Run(c *kubernetes.Clientset, rc rest.Config, nc config.Config,
 	// VM mode
 	if virt {
 		retry := 10
-		present := false
 		var err error
 		createdClient := false
 		var vmClient config.VMExecutor
@@ -91,25 +90,6 @@ func (n *netperf) Run(c *kubernetes.Clientset, rc rest.Config, nc config.Config,
 			}
 			createdClient = true
 		}
-		for i := 0; i <= retry; i++ {
-			log.Debug("⏰ Waiting for netperf to be present on VM")
-			_, err = vmClient.Run("until which netperf; do sleep 30; done")
-			if err == nil {
-				present = true
-				break
-			} else {
-				log.Debugf("Failed running command %s", err)
-			}
-			time.Sleep(30 * time.Second)
-		}
-		if !present {
-			if createdClient {
-				if err := vmClient.Close(); err != nil {
-					log.Warnf("Error closing VM client: %v", err)
-				}
-			}
-			return stdout, fmt.Errorf("netperf binary is not present on the VM")
-		}
 		var stdout []byte
 		ran := false
 		for i := 0; i <= retry; i++ {
@@ -227,3 +207,41 @@ func (n *netperf) ParseResults(stdout *bytes.Buffer, _ config.Config) (sample.Sa
 func (n *netperf) IsTestSupported() bool {
 	return n.testConfig.Profile != "TCP_STREAM_LAT"
 }
+
+// Intervals, in seconds, of the time series of a migration test: the interim results of
+// netperf demo mode, and the ping probes towards the server.
+const (
+	DemoInterval  = 0.5
+	ProbeInterval = 0.2
+)
+
+// MigrationSeparator separates the outputs of a migration test.
+const MigrationSeparator = "@@@@"
+
+// MigrationCommand returns the shell command which starts a migration test in the background
+// of the client VMI: a single netperf in demo mode, reporting every DemoInterval, and ping probes
+// towards the server alongside. The test outlives the ssh session, which a migration of the
+// client VMI breaks. The outputs are written to files with the prefix.
+func MigrationCommand(nc config.Config, serverIP, prefix string) string {
+	test := []string{"netperf", "-H", serverIP, "-l", fmt.Sprint(nc.Duration), "-t", nc.Profile,
+		"-D", fmt.Sprint(DemoInterval),
+		"--", "-k", omniOptions, "-P", strconv.Itoa(k8s.NetperfServerDataPort)}
+	if strings.Contains(nc.Profile, "STREAM") {
+		test = append(test, "-m", fmt.Sprint(nc.MessageSize))
+	} else {
+		test = append(test, "-r", fmt.Sprint(nc.MessageSize, ",", nc.MessageSize))
+		if strings.Contains(nc.Profile, "TCP_RR") && nc.Burst > 0 {
+			test = append(test, "-b", fmt.Sprint(nc.Burst))
+		}
+	}
+	probes := fmt.Sprintf("ping -D -O -i %v -w %d %s", ProbeInterval, nc.Duration, serverIP)
+	return fmt.Sprintf("rm -f %[1]s.*; nohup sh -c 'date +%%s.%%N > %[1]s.start; %[2]s > %[1]s.ping 2>&1 & %[3]s > %[1]s.netperf 2>&1; wait; touch %[1]s.done' > /dev/null 2>&1 &",
+		prefix, probes, strings.Join(test, " "))
+}
+
+// MigrationOutputCommand returns the shell command which prints the start time on the VMI, the
+// netperf output and the ping output of the migration test, separated by MigrationSeparator,
+// once the test is over, and nothing before.
+func MigrationOutputCommand(prefix string) string {
+	return fmt.Sprintf("if [ -f %[1]s.done ]; then cat %[1]s.start; echo %[2]s; cat %[1]s.netperf; echo %[2]s; cat %[1]s.ping; fi", prefix, MigrationSeparator)
+}
@@ -200,7 +200,7 @@ func (u *uperf) Run(c *kubernetes.Clientset, rc rest.Config, nc config.Config, c
 			clientIp = udnIp
 		}
 	} else if perf.Cudn {
-		if udnIp, _ := k8s.ExtractUdnIp(pod, k8s.CudnName); udnIp != "" {
+		if udnIp, _ := k8s.ExtractUdnIp(pod, k8s.CudnName()); udnIp != "" {
 			clientIp = udnIp
 		}
 	} else if perf.BridgeNetwork != "" {
@@ -231,7 +231,6 @@ func (u *uperf) Run(c *kubernetes.Clientset, rc rest.Config, nc config.Config, c
 	// VM mode
 	if virt {
 		retry := 10
-		present := false
 
 		createdClient := false
 		var vmClient config.VMExecutor
@@ -246,25 +245,6 @@ func (u *uperf) Run(c *kubernetes.Clientset, rc rest.Config, nc config.Config, c
 		}
 
 		var err error
-		for i := 0; i <= retry; i++ {
-			log.Debug("⏰ Waiting for uperf to be present on VM")
-			_, err = vmClient.Run("until uperf -h; do sleep 30; done")
-			if err == nil {
-				present = true
-				break
-			} else {
-				log.Debugf("Failed running command %s", err)
-			}
-			time.Sleep(30 * time.Second)
-		}
-		if !present {
-			if createdClient {
-				if err := vmClient.Close(); err != nil {
-					log.Warnf("Error closing VM client: %v", err)
-				}
-			}
-			return stdout, fmt.Errorf("uperf binary is not present on the VM")
-		}
 		var stdout []byte
 		ran := false
 		for i := 0; i <= retry; i++ {
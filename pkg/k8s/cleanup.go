@@ -0,0 +1,80 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/cloud-bulldozer/k8s-netperf/pkg/logging"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+// runUUID is the UUID of the run, recorded on the cluster-scoped resources it creates, see SetRunUUID.
+var runUUID string
+
+// SetRunUUID sets the UUID of the run, so that k8s-netperf cleanup only removes the
+// cluster-scoped resources of that run.
+func SetRunUUID(uuid string) {
+	runUUID = uuid
+}
+
+// ownerAnnotations returns the annotations of the cluster-scoped resources created by the run.
+func ownerAnnotations() map[string]interface{} {
+	return map[string]interface{}{RunUUIDAnnotation: runUUID}
+}
+
+// NamespaceOwner returns the UUID of the run which owns the namespace, empty when the
+// namespace does not exist or was not created by k8s-netperf.
+func NamespaceOwner(client *kubernetes.Clientset, name string) (string, error) {
+	ns, err := client.CoreV1().Namespaces().Get(context.TODO(), name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return "", nil
+	} else if err != nil {
+		return "", fmt.Errorf("unable to get namespace %s: %v", name, err)
+	}
+	return ns.Annotations[RunUUIDAnnotation], nil
+}
+
+// cleanupOwned deletes the resources of gvr created by the run with the given UUID, or by
+// any run when uuid is empty. The names of these resources depend on the namespace of their
+// run, so they are found by their annotation, and resources not created by k8s-netperf are
+// never deleted.
+func cleanupOwned(dyn dynamic.Interface, kind string, gvr schema.GroupVersionResource, ns, uuid string) {
+	list, err := dyn.Resource(gvr).Namespace(ns).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		log.Errorf("Unable to list %s: %v", gvr.Resource, err)
+		return
+	}
+	for _, obj := range list.Items {
+		owner, ok := obj.GetAnnotations()[RunUUIDAnnotation]
+		if !ok || (uuid != "" && owner != uuid) {
+			log.Debugf("Skipping %s %s, it was not created by run %s", kind, obj.GetName(), uuid)
+			continue
+		}
+		deletePolicy := metav1.DeletePropagationForeground
+		err = dyn.Resource(gvr).Namespace(ns).Delete(context.TODO(), obj.GetName(), metav1.DeleteOptions{PropagationPolicy: &deletePolicy})
+		if err != nil && !apierrors.IsNotFound(err) {
+			log.Errorf("Unable to delete %s %s: %v", kind, obj.GetName(), err)
+		} else {
+			log.Infof("♻️ Deleted %s %s", kind, obj.GetName())
+		}
+	}
+}
+
+// CleanupCUdns deletes the ClusterUserDefinedNetworks created by the run with the given UUID,
+// or by any run when uuid is empty.
+func CleanupCUdns(dyn dynamic.Interface, uuid string) {
+	gvr := schema.GroupVersionResource{Group: "k8s.ovn.org", Version: "v1", Resource: "clusteruserdefinednetworks"}
+	cleanupOwned(dyn, "ClusterUserDefinedNetwork", gvr, "", uuid)
+}
+
+// CleanupSriov deletes the SriovNetworks and SriovNetworkNodePolicies created by the run with
+// the given UUID, or by any run when uuid is empty. Deleting a policy may reboot the nodes it
+// configured.
+func CleanupSriov(dyn dynamic.Interface, uuid string) {
+	cleanupOwned(dyn, "SriovNetwork", schema.GroupVersionResource{Group: "sriovnetwork.openshift.io", Version: "v1", Resource: "sriovnetworks"}, sriovOperatorNamespace, uuid)
+	cleanupOwned(dyn, "SriovNetworkNodePolicy", schema.GroupVersionResource{Group: "sriovnetwork.openshift.io", Version: "v1", Resource: "sriovnetworknodepolicies"}, sriovOperatorNamespace, uuid)
+}
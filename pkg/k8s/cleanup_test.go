@@ -0,0 +1,54 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func TestCleanupCUdns(t *testing.T) {
+	gvr := schema.GroupVersionResource{Group: "k8s.ovn.org", Version: "v1", Resource: "clusteruserdefinednetworks"}
+	cudn := func(name, owner string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion("k8s.ovn.org/v1")
+		u.SetKind("ClusterUserDefinedNetwork")
+		u.SetName(name)
+		if owner != "" {
+			u.SetAnnotations(map[string]string{RunUUIDAnnotation: owner})
+		}
+		return u
+	}
+	exists := func(dyn *dynamicfake.FakeDynamicClient, name string) bool {
+		_, err := dyn.Resource(gvr).Get(context.TODO(), name, metav1.GetOptions{})
+		return err == nil
+	}
+	newClient := func() *dynamicfake.FakeDynamicClient {
+		return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+			map[schema.GroupVersionResource]string{gvr: "ClusterUserDefinedNetworkList"},
+			cudn("cudn-secondary-netperf-a", "run-a"), cudn("cudn-localnet-netperf-b", "run-b"), cudn("team-network", ""))
+	}
+
+	dyn := newClient()
+	CleanupCUdns(dyn, "run-c")
+	if !exists(dyn, "cudn-secondary-netperf-a") || !exists(dyn, "cudn-localnet-netperf-b") {
+		t.Fatal("the CUDNs of another run were deleted")
+	}
+	CleanupCUdns(dyn, "run-a")
+	if exists(dyn, "cudn-secondary-netperf-a") || !exists(dyn, "cudn-localnet-netperf-b") {
+		t.Fatal("expected only the CUDN of run-a to be deleted")
+	}
+
+	dyn = newClient()
+	CleanupCUdns(dyn, "")
+	if exists(dyn, "cudn-secondary-netperf-a") || exists(dyn, "cudn-localnet-netperf-b") {
+		t.Fatal("expected the CUDNs of all the runs to be deleted without a run")
+	}
+	if !exists(dyn, "team-network") {
+		t.Fatal("a CUDN not created by k8s-netperf was deleted")
+	}
+}
@@ -0,0 +1,233 @@
+package k8s
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/config"
+	log "github.com/cloud-bulldozer/k8s-netperf/pkg/logging"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Labels of the extra clients of the incast tests, the extra servers of the fan-out tests,
+// and the client/server pairs of the pairs tests.
+const incastClientRole = "incast-client"
+const fanoutServerRole = "fanout-server"
+const pairClientRole = "pair-client"
+const pairServerRole = "pair-server"
+const spreadPairClientRole = "pair-client-spread"
+const spreadPairServerRole = "pair-server-spread"
+
+// workerSelector matches the nodes of workerNodeSelector.
+const workerSelector = "node-role.kubernetes.io/worker=,!node-role.kubernetes.io/infra,!node-role.kubernetes.io/workload"
+
+// groupSizes are the largest incast, fan-out and number of pairs of the tests.
+type groupSizes struct {
+	incast      int
+	fanout      int
+	pairs       int
+	spreadPairs int
+}
+
+func groupSizesOf(cfgs []config.Config) groupSizes {
+	var g groupSizes
+	for _, c := range cfgs {
+		g.incast = max(g.incast, c.Incast)
+		g.fanout = max(g.fanout, c.Fanout)
+		if c.Spread {
+			g.spreadPairs = max(g.spreadPairs, c.Pairs)
+		} else {
+			g.pairs = max(g.pairs, c.Pairs)
+		}
+	}
+	return g
+}
+
+// CheckGroups returns why the incast, fan-out and pairs tests can not run in the scenario,
+// nil when they can. They run pod to pod on the default network.
+func CheckGroups(s *config.PerfScenarios) error {
+	switch {
+	case !s.Pod || s.HostNetworkOnly:
+		return fmt.Errorf("incast, fan-out and pairs tests only run between pods")
+	case s.NodeLocal:
+		return fmt.Errorf("incast, fan-out and pairs tests do not run with --local")
+	case s.ExternalServer:
+		return fmt.Errorf("incast, fan-out and pairs tests do not run with an external server")
+	case s.Udn || s.Cudn || s.BridgeNetwork != "" || s.SriovNetwork != "" || s.MacvlanNetwork != "" || s.LocalnetNetwork != "":
+		return fmt.Errorf("incast, fan-out and pairs tests only run on the default pod network")
+	}
+	return nil
+}
+
+// roleAntiAffinity keeps the pods off the nodes running a pod with one of the roles.
+func roleAntiAffinity(roles ...string) corev1.PodAntiAffinity {
+	return corev1.PodAntiAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{
+			{
+				LabelSelector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{Key: "role", Operator: metav1.LabelSelectorOpIn, Values: roles},
+					},
+				},
+				TopologyKey: "kubernetes.io/hostname",
+			},
+		},
+	}
+}
+
+// groupNodes checks there are enough eligible nodes of the selection, besides the excluded node,
+// to run one pod per node. The workers are eligible when the selection is not set.
+func groupNodes(client *kubernetes.Clientset, sel nodeSelection, exclude string, want int) error {
+	if !sel.isSet() {
+		sel = nodeSelection{selector: workerSelector}
+	}
+	nodes, err := sel.eligibleNodes(client)
+	if err != nil {
+		return err
+	}
+	n := 0
+	for _, node := range nodes {
+		if node != exclude {
+			n++
+		}
+	}
+	if n < want && exclude != "" {
+		return fmt.Errorf("%d nodes are needed besides node %s, %d eligible nodes (%s)", want, exclude, n, sel)
+	} else if n < want {
+		return fmt.Errorf("%d nodes are needed, %d eligible nodes (%s)", want, n, sel)
+	}
+	return nil
+}
+
+// deployGroup deploys the replicas of dp on the workers of the selection, and returns its pods sorted by name.
+func deployGroup(client *kubernetes.Clientset, dp DeploymentParams, sel nodeSelection) (corev1.PodList, error) {
+	dp.NodeAffinity = corev1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: workerNodeSelector(),
+	}
+	if err := sel.pin(&dp.NodeAffinity); err != nil {
+		return corev1.PodList{}, err
+	}
+	pods, err := deployDeployment(client, dp)
+	if err != nil {
+		return pods, err
+	}
+	if len(pods.Items) < int(dp.Replicas) {
+		return pods, fmt.Errorf("%d of the %d %s pods are running", len(pods.Items), dp.Replicas, dp.Name)
+	}
+	sort.Slice(pods.Items, func(i, j int) bool { return pods.Items[i].Name < pods.Items[j].Name })
+	return pods, nil
+}
+
+// roleAffinity places the pods on the node running a pod with the role.
+func roleAffinity(role string) corev1.PodAffinity {
+	return corev1.PodAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{
+			{
+				LabelSelector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{Key: "role", Operator: metav1.LabelSelectorOpIn, Values: []string{role}},
+					},
+				},
+				TopologyKey: "kubernetes.io/hostname",
+			},
+		},
+	}
+}
+
+// buildGroups deploys the pods of the incast, fan-out and pairs tests. Nothing is deployed
+// when no test needs them.
+//   - the extra clients of the incast tests, each on its own node other than the server node.
+//   - the extra servers of the fan-out tests, each on its own node other than the client node.
+//   - the pairs, with the clients on the client node and the servers on the server node.
+//   - the spread pairs, each client and server on its own node.
+func buildGroups(client *kubernetes.Clientset, s *config.PerfScenarios) error {
+	g := groupSizesOf(s.Configs)
+	if g == (groupSizes{}) {
+		return nil
+	}
+	if err := CheckGroups(s); err != nil {
+		log.Warnf("%v, skipping them", err)
+		return nil
+	}
+	commands, err := serverCommands(s)
+	if err != nil {
+		return err
+	}
+	clientDp := func(role string, replicas int) DeploymentParams {
+		return DeploymentParams{
+			Name:       role,
+			Namespace:  namespace,
+			Replicas:   int32(replicas),
+			Image:      netperfImage,
+			Labels:     map[string]string{"role": role},
+			Commands:   [][]string{{"/bin/bash", "-c", "sleep 10000000"}},
+			Port:       NetperfServerCtlPort,
+			Privileged: s.Privileged,
+		}
+	}
+	serverDp := func(role string, replicas int) DeploymentParams {
+		dp := clientDp(role, replicas)
+		dp.Commands = commands
+		return dp
+	}
+	if g.incast > 0 {
+		if err := groupNodes(client, clientSelection(s), s.ServerNodeInfo.NodeName, g.incast); err != nil {
+			return fmt.Errorf("unable to run incast %d: %v", g.incast, err)
+		}
+		dp := clientDp(incastClientRole, g.incast)
+		dp.PodAntiAffinity = roleAntiAffinity(incastClientRole, serverRole)
+		if s.IncastClients, err = deployGroup(client, dp, clientSelection(s)); err != nil {
+			return err
+		}
+	}
+	if g.fanout > 0 {
+		if err := groupNodes(client, serverSelection(s), s.ClientNodeInfo.NodeName, g.fanout); err != nil {
+			return fmt.Errorf("unable to run fan-out %d: %v", g.fanout, err)
+		}
+		dp := serverDp(fanoutServerRole, g.fanout)
+		dp.PodAntiAffinity = roleAntiAffinity(fanoutServerRole, clientAcrossRole)
+		if s.FanoutServers, err = deployGroup(client, dp, serverSelection(s)); err != nil {
+			return err
+		}
+	}
+	if g.pairs > 0 {
+		// The client and server nodes are already selected, the affinity is enough.
+		dp := clientDp(pairClientRole, g.pairs)
+		dp.PodAffinity = roleAffinity(clientAcrossRole)
+		if s.PairClients, err = deployGroup(client, dp, nodeSelection{}); err != nil {
+			return err
+		}
+		dp = serverDp(pairServerRole, g.pairs)
+		dp.PodAffinity = roleAffinity(serverRole)
+		if s.PairServers, err = deployGroup(client, dp, nodeSelection{}); err != nil {
+			return err
+		}
+	}
+	if g.spreadPairs > 0 {
+		cs, ss := clientSelection(s), serverSelection(s)
+		if err := groupNodes(client, cs, "", g.spreadPairs); err != nil {
+			return fmt.Errorf("unable to run %d spread pairs: %v", g.spreadPairs, err)
+		}
+		if err := groupNodes(client, ss, "", g.spreadPairs); err != nil {
+			return fmt.Errorf("unable to run %d spread pairs: %v", g.spreadPairs, err)
+		}
+		if !cs.isSet() && !ss.isSet() {
+			if err := groupNodes(client, nodeSelection{}, "", 2*g.spreadPairs); err != nil {
+				return fmt.Errorf("unable to run %d spread pairs: %v", g.spreadPairs, err)
+			}
+		}
+		dp := clientDp(spreadPairClientRole, g.spreadPairs)
+		dp.PodAntiAffinity = roleAntiAffinity(spreadPairClientRole, spreadPairServerRole)
+		if s.SpreadPairClients, err = deployGroup(client, dp, cs); err != nil {
+			return err
+		}
+		dp = serverDp(spreadPairServerRole, g.spreadPairs)
+		dp.PodAntiAffinity = roleAntiAffinity(spreadPairClientRole, spreadPairServerRole)
+		if s.SpreadPairServers, err = deployGroup(client, dp, ss); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/config"
+	log "github.com/cloud-bulldozer/k8s-netperf/pkg/logging"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
+)
+
+// DefaultImage is the image of the k8s-netperf pods, unless set by SetImage.
+const DefaultImage = "quay.io/cloud-bulldozer/k8s-netperf:latest"
+
+// Image of the k8s-netperf pods and how it is pulled, see SetImage.
+var (
+	netperfImage = DefaultImage
+	pullPolicy   = corev1.PullAlways
+	pullSecrets  []string
+)
+
+var digestRegex = regexp.MustCompile(`^sha256:[0-9a-f]{64}$`)
+
+// SetImage sets the image of the k8s-netperf pods. The name, DefaultImage when not set, is
+// moved to the mirror registry and pinned to the digest when they are set. The pods pull
+// the image Always, unless another pull policy is set.
+func SetImage(img config.Image) error {
+	name := img.Name
+	if name == "" {
+		name = DefaultImage
+	}
+	if img.Mirror != "" {
+		name = mirrorImage(name, img.Mirror)
+	}
+	if img.Digest != "" {
+		if !digestRegex.MatchString(img.Digest) {
+			return fmt.Errorf("invalid image digest %q, expected sha256:<64 hex characters>", img.Digest)
+		}
+		name = imageRepository(name) + "@" + img.Digest
+	}
+	policy := corev1.PullAlways
+	switch p := corev1.PullPolicy(img.PullPolicy); p {
+	case "":
+	case corev1.PullAlways, corev1.PullIfNotPresent, corev1.PullNever:
+		policy = p
+	default:
+		return fmt.Errorf("invalid image pull policy %q, expected Always, IfNotPresent or Never", img.PullPolicy)
+	}
+	for _, ps := range img.PullSecrets {
+		ns, n, ok := strings.Cut(ps, "/")
+		if !ok || len(validation.IsDNS1123Label(ns)) > 0 || len(validation.IsDNS1123Subdomain(n)) > 0 {
+			return fmt.Errorf("invalid image pull secret %q, expected namespace/name", ps)
+		}
+	}
+	netperfImage, pullPolicy, pullSecrets = name, policy, img.PullSecrets
+	return nil
+}
+
+// Image returns the image of the k8s-netperf pods.
+func Image() string {
+	return netperfImage
+}
+
+// mirrorImage replaces the registry of the image name with the mirror. A name without
+// a registry, e.g. busybox:latest, is prefixed by the mirror.
+func mirrorImage(name, mirror string) string {
+	mirror = strings.TrimSuffix(mirror, "/")
+	registry, path, ok := strings.Cut(name, "/")
+	if ok && (strings.ContainsAny(registry, ".:") || registry == "localhost") {
+		return mirror + "/" + path
+	}
+	return mirror + "/" + name
+}
+
+// imageRepository returns the image name without its tag and digest.
+func imageRepository(name string) string {
+	name, _, _ = strings.Cut(name, "@")
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name = name[:i]
+	}
+	return name
+}
+
+// pullSecretRefs returns the references to the pull secrets copied in the namespace.
+func pullSecretRefs() []corev1.LocalObjectReference {
+	var refs []corev1.LocalObjectReference
+	for _, ps := range pullSecrets {
+		_, n, _ := strings.Cut(ps, "/")
+		refs = append(refs, corev1.LocalObjectReference{Name: n})
+	}
+	return refs
+}
+
+// copyPullSecrets copies the pull secrets in the namespace, the pods can only use the secrets of
+// their namespace. The copies are removed with the namespace.
+func copyPullSecrets(client *kubernetes.Clientset) error {
+	for _, ps := range pullSecrets {
+		ns, n, _ := strings.Cut(ps, "/")
+		if ns == namespace {
+			continue
+		}
+		if _, err := client.CoreV1().Secrets(namespace).Get(context.TODO(), n, metav1.GetOptions{}); err == nil {
+			log.Infof("♻️ Pull secret %s already exists, reusing it", n)
+			continue
+		}
+		secret, err := client.CoreV1().Secrets(ns).Get(context.TODO(), n, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("😥 Unable to get pull secret %s: %v", ps, err)
+		}
+		log.Infof("🔑 Copying pull secret %s", ps)
+		_, err = client.CoreV1().Secrets(namespace).Create(context.TODO(), &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: n},
+			Type:       secret.Type,
+			Data:       secret.Data,
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("😥 Unable to copy pull secret %s: %v", ps, err)
+		}
+	}
+	return nil
+}
+
+// ImageDigest returns the digest of the image the k8s-netperf containers of the pods run,
+// as resolved by the kubelet, or "" when none of the pods reports it.
+func ImageDigest(pods ...corev1.PodList) string {
+	for _, pl := range pods {
+		for _, pod := range pl.Items {
+			for _, cs := range pod.Status.ContainerStatuses {
+				if _, digest, ok := strings.Cut(cs.ImageID, "@"); ok {
+					return digest
+				}
+			}
+		}
+	}
+	return ""
+}
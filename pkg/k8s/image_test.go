@@ -0,0 +1,74 @@
+package k8s
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestSetImage(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("ab", 32)
+	tests := []struct {
+		name    string
+		img     config.Image
+		want    string
+		policy  corev1.PullPolicy
+		wantErr bool
+	}{
+		{"default", config.Image{}, DefaultImage, corev1.PullAlways, false},
+		{"name", config.Image{Name: "registry.local/netperf:v1", PullPolicy: "IfNotPresent"}, "registry.local/netperf:v1", corev1.PullIfNotPresent, false},
+		{"mirror", config.Image{Mirror: "mirror.local:5000/"}, "mirror.local:5000/cloud-bulldozer/k8s-netperf:latest", corev1.PullAlways, false},
+		{"mirror without registry", config.Image{Name: "netperf:v1", Mirror: "mirror.local"}, "mirror.local/netperf:v1", corev1.PullAlways, false},
+		{"digest", config.Image{Digest: digest}, "quay.io/cloud-bulldozer/k8s-netperf@" + digest, corev1.PullAlways, false},
+		{"digest with port", config.Image{Name: "localhost:5000/netperf", Digest: digest}, "localhost:5000/netperf@" + digest, corev1.PullAlways, false},
+		{"bad digest", config.Image{Digest: "sha256:abc"}, "", "", true},
+		{"bad pull policy", config.Image{PullPolicy: "Sometimes"}, "", "", true},
+		{"bad pull secret", config.Image{PullSecrets: []string{"pull-secret"}}, "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() { _ = SetImage(config.Image{}) }()
+			err := SetImage(tt.img)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SetImage(%+v) error = %v, wantErr %t", tt.img, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if Image() != tt.want || pullPolicy != tt.policy {
+				t.Fatalf("SetImage(%+v) = %s %s, want %s %s", tt.img, Image(), pullPolicy, tt.want, tt.policy)
+			}
+		})
+	}
+}
+
+func TestPullSecretRefs(t *testing.T) {
+	defer func() { _ = SetImage(config.Image{}) }()
+	if err := SetImage(config.Image{PullSecrets: []string{"openshift-config/pull-secret", "ci/quay"}}); err != nil {
+		t.Fatal(err)
+	}
+	refs := pullSecretRefs()
+	if len(refs) != 2 || refs[0].Name != "pull-secret" || refs[1].Name != "quay" {
+		t.Fatalf("pullSecretRefs() = %v", refs)
+	}
+	tpl := podTemplate(DeploymentParams{Name: "server", Image: Image(), Commands: [][]string{{"sleep"}}})
+	if len(tpl.Spec.ImagePullSecrets) != 2 || tpl.Spec.Containers[0].ImagePullPolicy != corev1.PullAlways {
+		t.Fatalf("the pod template does not use the pull settings: %+v", tpl.Spec)
+	}
+}
+
+func TestImageDigest(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("01", 32)
+	pending := corev1.PodList{Items: []corev1.Pod{{}}}
+	running := corev1.PodList{Items: []corev1.Pod{{Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+		{Image: DefaultImage, ImageID: "quay.io/cloud-bulldozer/k8s-netperf@" + digest},
+	}}}}}
+	if got := ImageDigest(pending, running); got != digest {
+		t.Fatalf("ImageDigest() = %q, want %q", got, digest)
+	}
+	if got := ImageDigest(pending); got != "" {
+		t.Fatalf("ImageDigest() of pods without status = %q, want none", got)
+	}
+}
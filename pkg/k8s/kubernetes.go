@@ -14,6 +14,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -43,6 +44,7 @@ type DeploymentParams struct {
 	Port               int
 	NetworkAnnotations map[string]string
 	ResourceRequests   corev1.ResourceList
+	RuntimeClassName   string
 }
 
 // ServiceParams describes the service specific details
@@ -62,7 +64,6 @@ type PodNetworksData struct {
 }
 
 const sa string = "netperf"
-const namespace string = "netperf"
 
 // NetperfServerCtlPort control port for the service
 const NetperfServerCtlPort = 12865
@@ -101,12 +102,7 @@ const clientRole = "client-local"
 const clientAcrossRole = "client-across"
 const hostNetServerRole = "host-server"
 const hostNetClientRole = "host-client"
-const k8sNetperfImage = "quay.io/cloud-bulldozer/k8s-netperf:latest"
 const UdnName = "udn-primary-netperf"
-const CudnName = "cudn-secondary-netperf"
-const LocalnetCudnName = "cudn-localnet-netperf"
-const SriovNadName = "sriov-netperf"
-const SriovPolicyName = "sriov-netperf-policy"
 const sriovOperatorNamespace = "openshift-sriov-network-operator"
 const MacvlanNadName = "macvlan-netperf"
 const netperfNamespaceLabel = "k8s-netperf"
@@ -163,8 +159,8 @@ func buildCUdnNetworkAnnotations(cudn string) map[string]string {
 // buildSriovNetworkAnnotations creates the network annotations for Multus CNI SR-IOV networks
 func buildSriovNetworkAnnotations() map[string]string {
 	annotations := make(map[string]string)
-	annotations["k8s.v1.cni.cncf.io/networks"] = namespace + "/" + SriovNadName
-	log.Infof("🌉 Configuring SR-IOV network: %s", namespace+"/"+SriovNadName)
+	annotations["k8s.v1.cni.cncf.io/networks"] = namespace + "/" + SriovNadName()
+	log.Infof("🌉 Configuring SR-IOV network: %s", namespace+"/"+SriovNadName())
 	return annotations
 }
 
@@ -238,19 +234,23 @@ func ExtractMacvlanIp(pod corev1.Pod) (string, error) {
 	return "", fmt.Errorf("MACVLAN network IP not found for %s on pod %s", expectedNetworkName, pod.Name)
 }
 
-// BuildInfra will create the infra for the SUT
-func BuildInfra(client *kubernetes.Clientset, udn bool) error {
-	_, err := client.CoreV1().Namespaces().Get(context.TODO(), namespace, metav1.GetOptions{})
+// BuildInfra will create the infra for the SUT of the run with the given UUID.
+// An existing namespace is only reused when it was created by the same run.
+func BuildInfra(client *kubernetes.Clientset, udn bool, uuid string) error {
+	ns, err := client.CoreV1().Namespaces().Get(context.TODO(), namespace, metav1.GetOptions{})
 	if err == nil {
+		if err := checkNamespaceOwner(ns, uuid); err != nil {
+			return err
+		}
 		log.Infof("♻️ Namespace already exists, reusing it")
 	} else {
 		log.Infof("🔨 Creating namespace: %s", namespace)
 		if udn {
-			_, err = client.CoreV1().Namespaces().Create(context.TODO(), &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace,
-				Labels: map[string]string{"k8s.ovn.org/primary-user-defined-network": ""}}}, metav1.CreateOptions{})
+			_, err = client.CoreV1().Namespaces().Create(context.TODO(), &corev1.Namespace{ObjectMeta: namespaceMeta(uuid,
+				map[string]string{"k8s.ovn.org/primary-user-defined-network": ""})}, metav1.CreateOptions{})
 		} else {
-			_, err = client.CoreV1().Namespaces().Create(context.TODO(), &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace,
-				Labels: map[string]string{namespace: netperfNamespaceLabel}}}, metav1.CreateOptions{})
+			_, err = client.CoreV1().Namespaces().Create(context.TODO(), &corev1.Namespace{ObjectMeta: namespaceMeta(uuid,
+				map[string]string{namespace: netperfNamespaceLabel})}, metav1.CreateOptions{})
 		}
 		if err != nil {
 			return fmt.Errorf("😥 Unable to create namespace: %v", err)
@@ -292,7 +292,7 @@ func BuildInfra(client *kubernetes.Clientset, udn bool) error {
 			return fmt.Errorf("😥 Unable to create role-binding: %v", err)
 		}
 	}
-	return nil
+	return copyPullSecrets(client)
 }
 
 // Create a User Defined Network for the tests
@@ -383,8 +383,9 @@ func DeployCUDN(dynamicClient *dynamic.DynamicClient, cudn string) error {
 			"apiVersion": "k8s.ovn.org/v1",
 			"kind":       "ClusterUserDefinedNetwork",
 			"metadata": map[string]interface{}{
-				"name":      CudnName,
-				"namespace": namespace,
+				"name":        CudnName(),
+				"namespace":   namespace,
+				"annotations": ownerAnnotations(),
 			},
 			"spec": map[string]interface{}{
 				"namespaceSelector": map[string]interface{}{
@@ -442,7 +443,8 @@ func DeployLocalnetCUDN(dynamicClient *dynamic.DynamicClient, externalNetworkNam
 			"apiVersion": "k8s.ovn.org/v1",
 			"kind":       "ClusterUserDefinedNetwork",
 			"metadata": map[string]interface{}{
-				"name": LocalnetCudnName,
+				"name":        LocalnetCudnName(),
+				"annotations": ownerAnnotations(),
 			},
 			"spec": map[string]interface{}{
 				"namespaceSelector": map[string]interface{}{
@@ -505,8 +507,12 @@ func DeployNADBridge(dyn *dynamic.DynamicClient, bridgeName string) error {
 	return nil
 }
 
-// DeploySriovOperatorConfig applies the SriovOperatorConfig with disableDrain to avoid node drain/reboot
-func DeploySriovOperatorConfig(dyn *dynamic.DynamicClient, nodeRole string) error {
+// sriovOperatorConfigFields are the fields of the spec of the SriovOperatorConfig set by k8s-netperf.
+var sriovOperatorConfigFields = []string{"configDaemonNodeSelector", "disableDrain"}
+
+// DeploySriovOperatorConfig applies the SriovOperatorConfig with disableDrain to avoid node drain/reboot.
+// It returns the previous values of the fields it sets, see restoreSriovOperatorConfig.
+func DeploySriovOperatorConfig(dyn *dynamic.DynamicClient, nodeRole string) (map[string]interface{}, error) {
 	log.Infof("Applying SriovOperatorConfig with disableDrain: true")
 	gvr := schema.GroupVersionResource{
 		Group:    "sriovnetwork.openshift.io",
@@ -515,12 +521,18 @@ func DeploySriovOperatorConfig(dyn *dynamic.DynamicClient, nodeRole string) erro
 	}
 	existing, err := dyn.Resource(gvr).Namespace(sriovOperatorNamespace).Get(context.TODO(), "default", metav1.GetOptions{})
 	if err != nil {
-		return fmt.Errorf("unable to get SriovOperatorConfig: %v", err)
+		return nil, fmt.Errorf("unable to get SriovOperatorConfig: %v", err)
 	}
 	spec, _ := existing.Object["spec"].(map[string]interface{})
 	if spec == nil {
 		spec = make(map[string]interface{})
 	}
+	prev := map[string]interface{}{}
+	for _, f := range sriovOperatorConfigFields {
+		if v, ok := spec[f]; ok {
+			prev[f] = v
+		}
+	}
 	spec["configDaemonNodeSelector"] = map[string]interface{}{
 		"node-role.kubernetes.io/" + nodeRole: "",
 	}
@@ -528,9 +540,41 @@ func DeploySriovOperatorConfig(dyn *dynamic.DynamicClient, nodeRole string) erro
 	existing.Object["spec"] = spec
 	_, err = dyn.Resource(gvr).Namespace(sriovOperatorNamespace).Update(context.TODO(), existing, metav1.UpdateOptions{})
 	if err != nil {
-		return fmt.Errorf("unable to update SriovOperatorConfig: %v", err)
+		return nil, fmt.Errorf("unable to update SriovOperatorConfig: %v", err)
 	}
-	return nil
+	return prev, nil
+}
+
+// restoreSriovOperatorConfig restores the fields of the SriovOperatorConfig set by
+// DeploySriovOperatorConfig to their previous values. Nothing is restored when prev is nil,
+// i.e. the SriovOperatorConfig was not updated.
+func restoreSriovOperatorConfig(dyn *dynamic.DynamicClient, prev map[string]interface{}) error {
+	if prev == nil {
+		return nil
+	}
+	gvr := schema.GroupVersionResource{
+		Group:    "sriovnetwork.openshift.io",
+		Version:  "v1",
+		Resource: "sriovoperatorconfigs",
+	}
+	existing, err := dyn.Resource(gvr).Namespace(sriovOperatorNamespace).Get(context.TODO(), "default", metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	spec, _ := existing.Object["spec"].(map[string]interface{})
+	if spec == nil {
+		spec = make(map[string]interface{})
+	}
+	for _, f := range sriovOperatorConfigFields {
+		if v, ok := prev[f]; ok {
+			spec[f] = v
+		} else {
+			delete(spec, f)
+		}
+	}
+	existing.Object["spec"] = spec
+	_, err = dyn.Resource(gvr).Namespace(sriovOperatorNamespace).Update(context.TODO(), existing, metav1.UpdateOptions{})
+	return err
 }
 
 // DeploySriovPolicy creates a SriovNetworkNodePolicy CR to carve VFs from a PF
@@ -545,8 +589,9 @@ func DeploySriovPolicy(dyn *dynamic.DynamicClient, pfName string, nodeRole strin
 			"apiVersion": "sriovnetwork.openshift.io/v1",
 			"kind":       "SriovNetworkNodePolicy",
 			"metadata": map[string]interface{}{
-				"name":      SriovPolicyName,
-				"namespace": sriovOperatorNamespace,
+				"name":        SriovPolicyName(),
+				"namespace":   sriovOperatorNamespace,
+				"annotations": ownerAnnotations(),
 			},
 			"spec": map[string]interface{}{
 				"resourceName": pfName,
@@ -581,8 +626,9 @@ func DeploySriovNetwork(dyn *dynamic.DynamicClient, resourceName string) error {
 			"apiVersion": "sriovnetwork.openshift.io/v1",
 			"kind":       "SriovNetwork",
 			"metadata": map[string]interface{}{
-				"name":      SriovNadName,
-				"namespace": sriovOperatorNamespace,
+				"name":        SriovNadName(),
+				"namespace":   sriovOperatorNamespace,
+				"annotations": ownerAnnotations(),
 			},
 			"spec": map[string]interface{}{
 				"networkNamespace": namespace,
@@ -605,7 +651,7 @@ func DeploySriovNetwork(dyn *dynamic.DynamicClient, resourceName string) error {
 
 // WaitForSriovNad polls for the SR-IOV NAD to be created in the netperf namespace
 func WaitForSriovNad(dyn *dynamic.DynamicClient) error {
-	log.Infof("Waiting for SR-IOV NetworkAttachmentDefinition %s in namespace %s...", SriovNadName, namespace)
+	log.Infof("Waiting for SR-IOV NetworkAttachmentDefinition %s in namespace %s...", SriovNadName(), namespace)
 	gvr := schema.GroupVersionResource{
 		Group:    "k8s.cni.cncf.io",
 		Version:  "v1",
@@ -613,15 +659,15 @@ func WaitForSriovNad(dyn *dynamic.DynamicClient) error {
 	}
 	// Poll every 5 seconds for up to 120 seconds
 	for i := 0; i < 24; i++ {
-		_, err := dyn.Resource(gvr).Namespace(namespace).Get(context.TODO(), SriovNadName, metav1.GetOptions{})
+		_, err := dyn.Resource(gvr).Namespace(namespace).Get(context.TODO(), SriovNadName(), metav1.GetOptions{})
 		if err == nil {
-			log.Infof("SR-IOV NetworkAttachmentDefinition %s is ready", SriovNadName)
+			log.Infof("SR-IOV NetworkAttachmentDefinition %s is ready", SriovNadName())
 			return nil
 		}
 		log.Debugf("NAD not yet available, retrying in 5s... (%d/24)", i+1)
 		time.Sleep(5 * time.Second)
 	}
-	return fmt.Errorf("timed out waiting for SR-IOV NAD %s in namespace %s", SriovNadName, namespace)
+	return fmt.Errorf("timed out waiting for SR-IOV NAD %s in namespace %s", SriovNadName(), namespace)
 }
 
 // DestroySriovResources cleans up the SriovNetwork and SriovNetworkNodePolicy CRs
@@ -635,7 +681,7 @@ func DestroySriovResources(dyn *dynamic.DynamicClient) error {
 		Version:  "v1",
 		Resource: "sriovnetworks",
 	}
-	err := dyn.Resource(gvrNet).Namespace(sriovOperatorNamespace).Delete(context.TODO(), SriovNadName, opts)
+	err := dyn.Resource(gvrNet).Namespace(sriovOperatorNamespace).Delete(context.TODO(), SriovNadName(), opts)
 	if err != nil {
 		log.Warnf("Failed to delete SriovNetwork: %v", err)
 	}
@@ -645,7 +691,7 @@ func DestroySriovResources(dyn *dynamic.DynamicClient) error {
 		Version:  "v1",
 		Resource: "sriovnetworknodepolicies",
 	}
-	err = dyn.Resource(gvrPolicy).Namespace(sriovOperatorNamespace).Delete(context.TODO(), SriovPolicyName, opts)
+	err = dyn.Resource(gvrPolicy).Namespace(sriovOperatorNamespace).Delete(context.TODO(), SriovPolicyName(), opts)
 	if err != nil {
 		log.Warnf("Failed to delete SriovNetworkNodePolicy: %v", err)
 	}
@@ -654,20 +700,21 @@ func DestroySriovResources(dyn *dynamic.DynamicClient) error {
 
 // BuildSUT Build the k8s env to run network performance tests
 func BuildSUT(client *kubernetes.Clientset, s *config.PerfScenarios) error {
-	var netperfDataPorts []int32
-	var netperfVmDataPorts []int32
-	var err error
-
-	// Build SR-IOV resource requests if needed
-	var sriovResources corev1.ResourceList
-	if s.SriovNetwork != "" {
-		sriovResources = corev1.ResourceList{
-			corev1.ResourceName("openshift.io/" + s.SriovNetwork): resource.MustParse("1"),
-		}
+	if err := buildSUT(client, s); err != nil {
+		return err
+	}
+	if err := buildGroups(client, s); err != nil {
+		return err
 	}
+	s.ClientNodeInfo.Selection = clientSelection(s).String()
+	s.ServerNodeInfo.Selection = serverSelection(s).String()
+	return nil
+}
 
-	// Schedule pods to nodes with role worker=, but not nodes with infra= and workload=
-	workerNodeSelectorExpression := &corev1.NodeSelector{
+// workerNodeSelector schedules pods to nodes with role worker=, but not nodes with infra= and workload=,
+// or to the nodes matching the extra node selector terms.
+func workerNodeSelector() *corev1.NodeSelector {
+	return withExtraTerms(&corev1.NodeSelector{
 		NodeSelectorTerms: []corev1.NodeSelectorTerm{
 			{
 				MatchExpressions: []corev1.NodeSelectorRequirement{
@@ -677,15 +724,31 @@ func BuildSUT(client *kubernetes.Clientset, s *config.PerfScenarios) error {
 				},
 			},
 		},
+	})
+}
+
+func buildSUT(client *kubernetes.Clientset, s *config.PerfScenarios) error {
+	var netperfDataPorts []int32
+	var netperfVmDataPorts []int32
+	var err error
+
+	// Build SR-IOV resource requests if needed
+	var sriovResources corev1.ResourceList
+	if s.SriovNetwork != "" {
+		sriovResources = corev1.ResourceList{
+			corev1.ResourceName("openshift.io/" + s.SriovNetwork): resource.MustParse("1"),
+		}
 	}
 
+	workerNodeSelectorExpression := workerNodeSelector()
+
 	if s.ExternalServer {
 		networkAnnotations := make(map[string]string)
 		//  Create Netperf client on any worker node. No service or server pod required in this scenario.
 		if s.BridgeNetwork != "" {
 			networkAnnotations = buildBridgeNetworkAnnotations(s.BridgeNetwork, s.BridgeNamespace)
 		} else if s.Cudn {
-			networkAnnotations = buildCUdnNetworkAnnotations(CudnName)
+			networkAnnotations = buildCUdnNetworkAnnotations(CudnName())
 		} else if s.SriovNetwork != "" {
 			networkAnnotations = buildSriovNetworkAnnotations()
 		} else if s.MacvlanNetwork != "" {
@@ -693,9 +756,9 @@ func BuildSUT(client *kubernetes.Clientset, s *config.PerfScenarios) error {
 		}
 		cdp := DeploymentParams{
 			Name:               "client",
-			Namespace:          "netperf",
+			Namespace:          namespace,
 			Replicas:           1,
-			Image:              k8sNetperfImage,
+			Image:              netperfImage,
 			Labels:             map[string]string{"role": clientRole},
 			Commands:           [][]string{{"/bin/bash", "-c", "sleep 10000000"}},
 			Port:               NetperfServerCtlPort,
@@ -707,6 +770,9 @@ func BuildSUT(client *kubernetes.Clientset, s *config.PerfScenarios) error {
 		cdp.NodeAffinity = corev1.NodeAffinity{
 			RequiredDuringSchedulingIgnoredDuringExecution: workerNodeSelectorExpression,
 		}
+		if err := clientSelection(s).pin(&cdp.NodeAffinity); err != nil {
+			return err
+		}
 
 		s.Client, err = deployDeployment(client, cdp)
 		if err != nil {
@@ -750,8 +816,12 @@ func BuildSUT(client *kubernetes.Clientset, s *config.PerfScenarios) error {
 	if err != nil {
 		return err
 	}
-	ncount := len(nodes.Items)
-	log.Debugf("Number of nodes with role worker: %d", ncount)
+	extra, err := extraNodes(client, nodes.Items)
+	if err != nil {
+		return err
+	}
+	ncount := len(nodes.Items) + extra
+	log.Debugf("Number of nodes with role worker or matching the extra node selector terms: %d", ncount)
 	if !s.NodeLocal && ncount < 2 {
 		return fmt.Errorf("not enough nodes with label worker= to execute test (current number of nodes: %d)", ncount)
 	}
@@ -774,7 +844,7 @@ func BuildSUT(client *kubernetes.Clientset, s *config.PerfScenarios) error {
 		if s.BridgeNetwork != "" {
 			networkAnnotations = buildBridgeNetworkAnnotations(s.BridgeNetwork, s.BridgeNamespace)
 		} else if s.Cudn {
-			networkAnnotations = buildCUdnNetworkAnnotations(CudnName)
+			networkAnnotations = buildCUdnNetworkAnnotations(CudnName())
 		} else if s.SriovNetwork != "" {
 			networkAnnotations = buildSriovNetworkAnnotations()
 		} else if s.MacvlanNetwork != "" {
@@ -782,10 +852,10 @@ func BuildSUT(client *kubernetes.Clientset, s *config.PerfScenarios) error {
 		}
 		cdp := DeploymentParams{
 			Name:               "client",
-			Namespace:          "netperf",
+			Namespace:          namespace,
 			Replicas:           1,
 			HostNetwork:        s.HostNetwork,
-			Image:              k8sNetperfImage,
+			Image:              netperfImage,
 			Labels:             map[string]string{"role": clientRole},
 			Commands:           [][]string{{"/bin/bash", "-c", "sleep 10000000"}},
 			Port:               NetperfServerCtlPort,
@@ -802,6 +872,9 @@ func BuildSUT(client *kubernetes.Clientset, s *config.PerfScenarios) error {
 		cdp.NodeAffinity = corev1.NodeAffinity{
 			RequiredDuringSchedulingIgnoredDuringExecution: workerNodeSelectorExpression,
 		}
+		if err := clientSelection(s).pin(&cdp.NodeAffinity); err != nil {
+			return err
+		}
 		if s.Pod {
 			s.Client, err = deployDeployment(client, cdp)
 			if err != nil {
@@ -814,16 +887,6 @@ func BuildSUT(client *kubernetes.Clientset, s *config.PerfScenarios) error {
 		}
 	}
 
-	// Helper function to check if a driver is requested
-	containsDriver := func(drivers []string, driver string) bool {
-		for _, d := range drivers {
-			if d == driver {
-				return true
-			}
-		}
-		return false
-	}
-
 	// Debug: Print requested drivers in BuildSUT
 	log.Debugf("🔥 BuildSUT: RequestedDrivers=%v, len=%d", s.RequestedDrivers, len(s.RequestedDrivers))
 
@@ -833,7 +896,7 @@ func BuildSUT(client *kubernetes.Clientset, s *config.PerfScenarios) error {
 			// Create iperf service
 			iperfSVC := ServiceParams{
 				Name:      "iperf-service",
-				Namespace: "netperf",
+				Namespace: namespace,
 				Labels:    map[string]string{"role": serverRole},
 				CtlPort:   IperfServerCtlPort,
 				DataPorts: []int32{IperfServerDataPort},
@@ -847,7 +910,7 @@ func BuildSUT(client *kubernetes.Clientset, s *config.PerfScenarios) error {
 			// Create iperf VM service
 			iperfSVC := ServiceParams{
 				Name:      "iperf-vm-service",
-				Namespace: "netperf",
+				Namespace: namespace,
 				Labels:    map[string]string{"role": vmServerRole},
 				CtlPort:   IperfServerCtlPort,
 				DataPorts: []int32{IperfVmServerDataPort},
@@ -864,7 +927,7 @@ func BuildSUT(client *kubernetes.Clientset, s *config.PerfScenarios) error {
 			// Create uperf service
 			uperfSVC := ServiceParams{
 				Name:      "uperf-service",
-				Namespace: "netperf",
+				Namespace: namespace,
 				Labels:    map[string]string{"role": serverRole},
 				CtlPort:   UperfServerCtlPort,
 				DataPorts: []int32{UperfServerDataPort},
@@ -876,7 +939,7 @@ func BuildSUT(client *kubernetes.Clientset, s *config.PerfScenarios) error {
 			// Create uperf-histogram service
 			uperfLatSVC := ServiceParams{
 				Name:      "uperf-histogram-service",
-				Namespace: "netperf",
+				Namespace: namespace,
 				Labels:    map[string]string{"role": serverRole},
 				CtlPort:   UperfLatServerCtlPort,
 				DataPorts: []int32{UperfLatServerDataPort},
@@ -889,7 +952,7 @@ func BuildSUT(client *kubernetes.Clientset, s *config.PerfScenarios) error {
 		if s.VM {
 			uperfSVC := ServiceParams{
 				Name:      "uperf-vm-service",
-				Namespace: "netperf",
+				Namespace: namespace,
 				Labels:    map[string]string{"role": vmServerRole},
 				CtlPort:   UperfServerCtlPort,
 				DataPorts: []int32{UperfVmServerDataPort},
@@ -901,7 +964,7 @@ func BuildSUT(client *kubernetes.Clientset, s *config.PerfScenarios) error {
 			//
 			uperfLatSVC := ServiceParams{
 				Name:      "uperf-vm-histogram-service",
-				Namespace: "netperf",
+				Namespace: namespace,
 				Labels:    map[string]string{"role": vmServerRole},
 				CtlPort:   UperfLatServerCtlPort,
 				DataPorts: []int32{UperfLatVmServerDataPort},
@@ -921,7 +984,7 @@ func BuildSUT(client *kubernetes.Clientset, s *config.PerfScenarios) error {
 			}
 			netperfSVC := ServiceParams{
 				Name:      "netperf-service",
-				Namespace: "netperf",
+				Namespace: namespace,
 				Labels:    map[string]string{"role": serverRole},
 				CtlPort:   NetperfServerCtlPort,
 				DataPorts: netperfDataPorts,
@@ -938,7 +1001,7 @@ func BuildSUT(client *kubernetes.Clientset, s *config.PerfScenarios) error {
 			}
 			netperfSVC := ServiceParams{
 				Name:      "netperf-vm-service",
-				Namespace: "netperf",
+				Namespace: namespace,
 				Labels:    map[string]string{"role": vmServerRole},
 				CtlPort:   NetperfServerCtlPort,
 				DataPorts: netperfVmDataPorts,
@@ -955,7 +1018,7 @@ func BuildSUT(client *kubernetes.Clientset, s *config.PerfScenarios) error {
 	if s.BridgeNetwork != "" {
 		networkAnnotations = buildBridgeNetworkAnnotations(s.BridgeNetwork, s.BridgeNamespace)
 	} else if s.Cudn {
-		networkAnnotations = buildCUdnNetworkAnnotations(CudnName)
+		networkAnnotations = buildCUdnNetworkAnnotations(CudnName())
 	} else if s.SriovNetwork != "" {
 		networkAnnotations = buildSriovNetworkAnnotations()
 	} else if s.MacvlanNetwork != "" {
@@ -963,9 +1026,9 @@ func BuildSUT(client *kubernetes.Clientset, s *config.PerfScenarios) error {
 	}
 	cdpAcross := DeploymentParams{
 		Name:               "client-across",
-		Namespace:          "netperf",
+		Namespace:          namespace,
 		Replicas:           1,
-		Image:              k8sNetperfImage,
+		Image:              netperfImage,
 		Labels:             map[string]string{"role": clientAcrossRole},
 		Commands:           [][]string{{"/bin/bash", "-c", "sleep 10000000"}},
 		Port:               NetperfServerCtlPort,
@@ -979,10 +1042,10 @@ func BuildSUT(client *kubernetes.Clientset, s *config.PerfScenarios) error {
 
 	cdpHostAcross := DeploymentParams{
 		Name:        "client-host",
-		Namespace:   "netperf",
+		Namespace:   namespace,
 		Replicas:    1,
 		HostNetwork: true,
-		Image:       k8sNetperfImage,
+		Image:       netperfImage,
 		Labels:      map[string]string{"role": hostNetClientRole},
 		Commands:    [][]string{{"/bin/bash", "-c", "sleep 10000000"}},
 		Port:        NetperfServerCtlPort,
@@ -1015,6 +1078,9 @@ func BuildSUT(client *kubernetes.Clientset, s *config.PerfScenarios) error {
 		cdpAcross.NodeAffinity = affinity
 		cdpHostAcross.NodeAffinity = affinity
 	}
+	if err := clientSelection(s).pin(&cdpAcross.NodeAffinity); err != nil {
+		return err
+	}
 
 	if ncount > 1 {
 		if s.HostNetwork {
@@ -1025,6 +1091,9 @@ func BuildSUT(client *kubernetes.Clientset, s *config.PerfScenarios) error {
 			cdpHostAcross.PodAntiAffinity = corev1.PodAntiAffinity{
 				RequiredDuringSchedulingIgnoredDuringExecution: clientRoleAffinity,
 			}
+			if err := clientSelection(s).pin(&cdpHostAcross.NodeAffinity); err != nil {
+				return err
+			}
 			if s.Pod {
 				s.ClientHost, err = deployDeployment(client, cdpHostAcross)
 				if err != nil {
@@ -1060,69 +1129,17 @@ func BuildSUT(client *kubernetes.Clientset, s *config.PerfScenarios) error {
 	}
 
 	// Use separate containers for servers
-	var dpCommands [][]string
-
-	// Debug: Print requested drivers for server commands
-	log.Debugf("🔥 Server Commands: RequestedDrivers=%v, len=%d", s.RequestedDrivers, len(s.RequestedDrivers))
-
-	// Add server commands only for requested drivers
-	if containsDriver(s.RequestedDrivers, "netperf") {
-		dpCommands = append(dpCommands, []string{"/bin/bash", "-c", "netserver && sleep 10000000"})
-	}
-	if containsDriver(s.RequestedDrivers, "iperf3") {
-		dpCommands = append(dpCommands, []string{"/bin/bash", "-c", fmt.Sprintf("iperf3 -s -p %d && sleep 10000000", IperfServerCtlPort)})
-	}
-	if containsDriver(s.RequestedDrivers, "uperf") {
-		// Check if any config uses TCP_STREAM_LAT profile
-		needsHistogram := false
-		for _, cfg := range s.Configs {
-			if cfg.Profile == "TCP_STREAM_LAT" {
-				needsHistogram = true
-				break
-			}
-		}
-
-		// Start uperf_histogram server only if TCP_STREAM_LAT is used
-		if needsHistogram {
-			dpCommands = append(dpCommands, []string{"/bin/bash", "-c", fmt.Sprintf("/opt/uperf-histogram/bin/uperf -s -v -P %d && sleep 10000000", UperfLatServerCtlPort)})
-		}
-
-		// Always start regular uperf server for other profiles
-		dpCommands = append(dpCommands, []string{"/bin/bash", "-c", fmt.Sprintf("uperf -s -v -P %d && sleep 10000000", UperfServerCtlPort)})
-	}
-	if containsDriver(s.RequestedDrivers, "ib_write_bw") {
-		// Parse nic:gid parameters for ib_write_bw server
-		parts := strings.Split(s.IbWriteBwParams, ":")
-		if len(parts) != 2 {
-			log.Fatalf("Invalid ib-write-bw server config: %s", s.IbWriteBwParams)
-		}
-		device := strings.TrimSpace(parts[0])
-		gid := strings.TrimSpace(parts[1])
-		// perftest requires server and client to use identical -D values
-		ibDuration := 10
-		for _, cfg := range s.Configs {
-			if strings.EqualFold(cfg.Profile, "UDP_STREAM") {
-				ibDuration = cfg.Duration
-				break
-			}
-		}
-		ibWriteCmd := fmt.Sprintf("stdbuf -oL -eL ib_write_bw -d %s -x %s -F -D %d", device, gid, ibDuration)
-		ibWriteCmd = fmt.Sprintf("while true; do %s; sleep 1; done", ibWriteCmd)
-		dpCommands = append(dpCommands, []string{"/bin/bash", "-c", ibWriteCmd})
-	}
-
-	// Debug: Print final dpCommands
-	log.Debugf("🔥 Final dpCommands count: %d", len(dpCommands))
-	for i, cmd := range dpCommands {
-		log.Debugf("🔥 dpCommand[%d]: %v", i, cmd)
+	dpCommands, err := serverCommands(s)
+	if err != nil {
+		return err
 	}
 
 	sdpHost := DeploymentParams{
 		Name:        "server-host",
-		Namespace:   "netperf",
+		Namespace:   namespace,
 		Replicas:    1,
 		HostNetwork: true,
-		Image:       k8sNetperfImage,
+		Image:       netperfImage,
 		Labels:      map[string]string{"role": hostNetServerRole},
 		Commands:    dpCommands,
 		Port:        NetperfServerCtlPort,
@@ -1131,9 +1148,9 @@ func BuildSUT(client *kubernetes.Clientset, s *config.PerfScenarios) error {
 	// Start netperf server
 	sdp := DeploymentParams{
 		Name:               "server",
-		Namespace:          "netperf",
+		Namespace:          namespace,
 		Replicas:           1,
-		Image:              k8sNetperfImage,
+		Image:              netperfImage,
 		Labels:             map[string]string{"role": serverRole},
 		Commands:           dpCommands,
 		Port:               NetperfServerCtlPort,
@@ -1184,6 +1201,12 @@ func BuildSUT(client *kubernetes.Clientset, s *config.PerfScenarios) error {
 		sdp.NodeAffinity = affinity
 		sdpHost.NodeAffinity = affinity
 	}
+	if err := serverSelection(s).pin(&sdp.NodeAffinity); err != nil {
+		return err
+	}
+	if err := serverSelection(s).pin(&sdpHost.NodeAffinity); err != nil {
+		return err
+	}
 	if ncount > 1 && !s.NodeLocal {
 		antiAffinity := corev1.PodAntiAffinity{
 			RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{
@@ -1248,6 +1271,11 @@ func BuildSUT(client *kubernetes.Clientset, s *config.PerfScenarios) error {
 			if err != nil {
 				return err
 			}
+			if s.SandboxRuntimeClass != "" {
+				if err := buildSandbox(client, s, cdpAcross, sdp); err != nil {
+					return err
+				}
+			}
 		}
 		if s.VM {
 			err = launchServerVM(s, vmServerRole, &sdp.PodAntiAffinity, &sdp.NodeAffinity)
@@ -1315,7 +1343,7 @@ func ExtractSriovIp(pod corev1.Pod) (string, error) {
 		return "", fmt.Errorf("error unmarshalling network status: %v", err)
 	}
 
-	expectedNetworkName := fmt.Sprintf("%s/%s", namespace, SriovNadName)
+	expectedNetworkName := fmt.Sprintf("%s/%s", namespace, SriovNadName())
 	for _, netStatus := range networkStatuses {
 		if netStatus.Name == expectedNetworkName && len(netStatus.IPs) > 0 {
 			for _, ip := range netStatus.IPs {
@@ -1364,11 +1392,99 @@ func ExtractUdnIp(pod corev1.Pod, networkName string) (string, error) {
 	return ipv4.String(), nil
 }
 
+// containsDriver checks if a driver is requested
+func containsDriver(drivers []string, driver string) bool {
+	for _, d := range drivers {
+		if d == driver {
+			return true
+		}
+	}
+	return false
+}
+
+// serverCommands returns the commands of the server containers, one per requested driver.
+func serverCommands(s *config.PerfScenarios) ([][]string, error) {
+	var dpCommands [][]string
+
+	// Debug: Print requested drivers for server commands
+	log.Debugf("🔥 Server Commands: RequestedDrivers=%v, len=%d", s.RequestedDrivers, len(s.RequestedDrivers))
+
+	// Add server commands only for requested drivers
+	if containsDriver(s.RequestedDrivers, "netperf") {
+		dpCommands = append(dpCommands, []string{"/bin/bash", "-c", "netserver && sleep 10000000"})
+	}
+	if containsDriver(s.RequestedDrivers, "iperf3") {
+		dpCommands = append(dpCommands, []string{"/bin/bash", "-c", fmt.Sprintf("iperf3 -s -p %d && sleep 10000000", IperfServerCtlPort)})
+	}
+	if containsDriver(s.RequestedDrivers, "uperf") {
+		// Check if any config uses TCP_STREAM_LAT profile
+		needsHistogram := false
+		for _, cfg := range s.Configs {
+			if cfg.Profile == "TCP_STREAM_LAT" {
+				needsHistogram = true
+				break
+			}
+		}
+
+		// Start uperf_histogram server only if TCP_STREAM_LAT is used
+		if needsHistogram {
+			dpCommands = append(dpCommands, []string{"/bin/bash", "-c", fmt.Sprintf("/opt/uperf-histogram/bin/uperf -s -v -P %d && sleep 10000000", UperfLatServerCtlPort)})
+		}
+
+		// Always start regular uperf server for other profiles
+		dpCommands = append(dpCommands, []string{"/bin/bash", "-c", fmt.Sprintf("uperf -s -v -P %d && sleep 10000000", UperfServerCtlPort)})
+	}
+	if containsDriver(s.RequestedDrivers, "ib_write_bw") {
+		// Parse nic:gid parameters for ib_write_bw server
+		parts := strings.Split(s.IbWriteBwParams, ":")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid ib-write-bw server config: %s", s.IbWriteBwParams)
+		}
+		device := strings.TrimSpace(parts[0])
+		gid := strings.TrimSpace(parts[1])
+		// perftest requires server and client to use identical -D values
+		ibDuration := 10
+		for _, cfg := range s.Configs {
+			if strings.EqualFold(cfg.Profile, "UDP_STREAM") {
+				ibDuration = cfg.Duration
+				break
+			}
+		}
+		ibWriteCmd := fmt.Sprintf("stdbuf -oL -eL ib_write_bw -d %s -x %s -F -D %d", device, gid, ibDuration)
+		ibWriteCmd = fmt.Sprintf("while true; do %s; sleep 1; done", ibWriteCmd)
+		dpCommands = append(dpCommands, []string{"/bin/bash", "-c", ibWriteCmd})
+	}
+
+	// Debug: Print final dpCommands
+	log.Debugf("🔥 Final dpCommands count: %d", len(dpCommands))
+	for i, cmd := range dpCommands {
+		log.Debugf("🔥 dpCommand[%d]: %v", i, cmd)
+	}
+	return dpCommands, nil
+}
+
+// launchVMToolsConfigMap returns the ConfigMap of the tools of the VMs, copied in the namespace,
+// "" when the VMs do not get their tools from a ConfigMap.
+func launchVMToolsConfigMap(perf *config.PerfScenarios) (string, error) {
+	if perf.VMTools != VMToolsConfigMap {
+		return "", nil
+	}
+	return vmToolsConfigMap(perf.ClientSet, perf.VMToolsConfigMap)
+}
+
 // launchServerVM will create the ServerVM with the specific node and pod affinity.
 func launchServerVM(perf *config.PerfScenarios, name string, podAff *corev1.PodAntiAffinity, nodeAff *corev1.NodeAffinity) error {
-	_, err := CreateVMServer(perf.KClient, name, name, *podAff, *nodeAff, perf.VMImage, perf.BridgeServerNetwork, perf.Udn, perf.UdnPluginBinding, perf.Cudn,
+	toolsConfigMap, err := launchVMToolsConfigMap(perf)
+	if err != nil {
+		return err
+	}
+	sshKey, err := authorizedKey(perf)
+	if err != nil {
+		return err
+	}
+	_, err = CreateVMServer(perf.KClient, name, name, *podAff, *nodeAff, perf.VMImage, perf.BridgeServerNetwork, perf.Udn, perf.UdnPluginBinding, perf.Cudn,
 		perf.LocalnetNetwork != "", perf.LocalnetServerNetwork,
-		perf.SriovNetwork, perf.Sockets, perf.Cores, perf.Threads)
+		perf.SriovNetwork, perf.VMSpec, perf.VMTools, toolsConfigMap, perf.VMAccess, sshKey)
 	if err != nil {
 		return err
 	}
@@ -1385,7 +1501,7 @@ func launchServerVM(perf *config.PerfScenarios, name string, podAff *corev1.PodA
 	perf.ServerNodeInfo, _ = GetPodNodeInfo(perf.ClientSet, fmt.Sprintf("app=%s", name))
 
 	if perf.SriovNetwork != "" && len(perf.VMServer.Items) > 0 {
-		err = ConfigureVMSriovIP(name, perf.VMServer.Items[0])
+		err = ConfigureVMSriovIP(perf, name, perf.VMServer.Items[0])
 		if err != nil {
 			return err
 		}
@@ -1395,9 +1511,17 @@ func launchServerVM(perf *config.PerfScenarios, name string, podAff *corev1.PodA
 
 // launchClientVM will create the ClientVM with the specific node and pod affinity.
 func launchClientVM(perf *config.PerfScenarios, name string, podAff *corev1.PodAntiAffinity, nodeAff *corev1.NodeAffinity) error {
+	toolsConfigMap, err := launchVMToolsConfigMap(perf)
+	if err != nil {
+		return err
+	}
+	sshKey, err := authorizedKey(perf)
+	if err != nil {
+		return err
+	}
 	host, err := CreateVMClient(perf.KClient, perf.ClientSet, perf.DClient, name, podAff, nodeAff, perf.VMImage, perf.BridgeClientNetwork, perf.Udn, perf.UdnPluginBinding, perf.Cudn,
 		perf.LocalnetNetwork != "", perf.LocalnetClientNetwork,
-		perf.SriovNetwork, perf.Sockets, perf.Cores, perf.Threads)
+		perf.SriovNetwork, perf.VMSpec, perf.VMTools, toolsConfigMap, perf.VMAccess, sshKey)
 	if err != nil {
 		return err
 	}
@@ -1412,9 +1536,17 @@ func launchClientVM(perf *config.PerfScenarios, name string, podAff *corev1.PodA
 		return err
 	}
 	perf.ClientNodeInfo, _ = GetPodNodeInfo(perf.ClientSet, fmt.Sprintf("app=%s", name))
+	vmi, err := perf.KClient.VirtualMachineInstances(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	spec := EffectiveVMSpec(vmi)
+	perf.VMEffectiveSpec = &spec
+	log.Infof("🖥️  VMI %s runs %d sockets, %d cores, %d threads, %s of memory (dedicated CPUs %t, NUMA %t, hugepages %q, multiqueue %t, machine %q, QoS %s)",
+		name, spec.Sockets, spec.Cores, spec.Threads, spec.Memory, spec.DedicatedCPUs, spec.NUMA, spec.Hugepages, spec.MultiQueue, spec.MachineType, spec.QOSClass)
 
 	if perf.SriovNetwork != "" && len(perf.VMClientAcross.Items) > 0 {
-		err = ConfigureVMSriovIP(name, perf.VMClientAcross.Items[0])
+		err = ConfigureVMSriovIP(perf, name, perf.VMClientAcross.Items[0])
 		if err != nil {
 			return err
 		}
@@ -1484,7 +1616,7 @@ func WaitForReady(c *kubernetes.Clientset, dp DeploymentParams) (bool, error) {
 			log.Error("❌ Issue with the Deployment")
 		}
 		if d.Name == dp.Name {
-			if d.Status.ReadyReplicas == 1 {
+			if d.Status.ReadyReplicas >= dp.Replicas {
 				return true, nil
 			}
 		}
@@ -1524,6 +1656,21 @@ func waitForNamespaceDelete(c *kubernetes.Clientset, nsName string) error {
 	return fmt.Errorf("❌ Namespace delete issues: %v", err)
 }
 
+// waitForDeploymentDelete polls for the deployment to be deleted.
+func waitForDeploymentDelete(c *kubernetes.Clientset, dp DeploymentParams) error {
+	// Poll every 2 seconds for up to 120 seconds
+	for i := 0; i < 60; i++ {
+		_, err := c.AppsV1().Deployments(dp.Namespace).Get(context.TODO(), dp.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		time.Sleep(2 * time.Second)
+	}
+	return fmt.Errorf("timed out waiting for Deployment %s in namespace %s to be deleted", dp.Name, dp.Namespace)
+}
+
 // GetZone will determine if we have a multiAZ/Zone cloud.
 // returns string of the zone, int of the node count in that zone, error if encountered a problem.
 func GetZone(c *kubernetes.Clientset) (string, map[string]int, error) {
@@ -1557,14 +1704,41 @@ func GetZone(c *kubernetes.Clientset) (string, map[string]int, error) {
 func CreateDeployment(dp DeploymentParams, client *kubernetes.Clientset) (*appsv1.Deployment, error) {
 	d, err := client.AppsV1().Deployments(dp.Namespace).Get(context.TODO(), dp.Name, metav1.GetOptions{})
 	if err == nil {
-		if d.Status.ReadyReplicas > 0 {
-			log.Info("♻️  Using existing Deployment")
+		// The namespace belongs to this run, see BuildInfra, a resumed run reuses its SUT.
+		if resuming && d.Status.ReadyReplicas > 0 {
+			log.Infof("♻️  Using existing Deployment %s in namespace %s", dp.Name, dp.Namespace)
 			return d, nil
 		}
+		// Otherwise the deployment is left by an earlier run, e.g. with --clean=false, and
+		// may not match the spec of this run.
+		log.Infof("♻️  Replacing existing Deployment %s in namespace %s", dp.Name, dp.Namespace)
+		if err := DestroyDeployment(client, *d); err != nil && !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		if err := waitForDeploymentDelete(client, dp); err != nil {
+			return nil, err
+		}
 	}
 	log.Infof("🚀 Starting Deployment for: %s in namespace: %s", dp.Name, dp.Namespace)
 	dc := client.AppsV1().Deployments(dp.Namespace)
 
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: dp.Name,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &dp.Replicas,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: dp.Labels,
+			},
+			Template: podTemplate(dp),
+		},
+	}
+	return dc.Create(context.TODO(), deployment, metav1.CreateOptions{})
+}
+
+// podTemplate returns the pod template of the deployment, or daemonset, described by dp.
+func podTemplate(dp DeploymentParams) corev1.PodTemplateSpec {
 	// Add containers to deployment
 	var cmdContainers []corev1.Container
 	for i := 0; i < len(dp.Commands); i++ {
@@ -1574,7 +1748,7 @@ func CreateDeployment(dp DeploymentParams, client *kubernetes.Clientset) (*appsv
 			Name:            containerName,
 			Image:           dp.Image,
 			Command:         dp.Commands[i],
-			ImagePullPolicy: corev1.PullAlways,
+			ImagePullPolicy: pullPolicy,
 		}
 
 		// Add privileged security context if requested
@@ -1585,11 +1759,9 @@ func CreateDeployment(dp DeploymentParams, client *kubernetes.Clientset) (*appsv
 		}
 
 		// Add resource requests (e.g., SR-IOV VFs)
-		if len(dp.ResourceRequests) > 0 {
-			container.Resources = corev1.ResourceRequirements{
-				Requests: dp.ResourceRequests,
-				Limits:   dp.ResourceRequests, // extended resources require limits == requests
-			}
+		container.Resources = containerResources(dp)
+		if hugepagesVolume() != nil {
+			container.VolumeMounts = []corev1.VolumeMount{{Name: "hugepages", MountPath: hugepagesMount}}
 		}
 
 		cmdContainers = append(cmdContainers, container)
@@ -1603,35 +1775,39 @@ func CreateDeployment(dp DeploymentParams, client *kubernetes.Clientset) (*appsv
 		annotations[k] = v
 	}
 
-	deployment := &appsv1.Deployment{
+	tpl := corev1.PodTemplateSpec{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: dp.Name,
+			Labels:      dp.Labels,
+			Annotations: annotations,
 		},
-		Spec: appsv1.DeploymentSpec{
-			Replicas: &dp.Replicas,
-			Selector: &metav1.LabelSelector{
-				MatchLabels: dp.Labels,
-			},
-			Template: corev1.PodTemplateSpec{
-				ObjectMeta: metav1.ObjectMeta{
-					Labels:      dp.Labels,
-					Annotations: annotations,
-				},
-				Spec: corev1.PodSpec{
-					TerminationGracePeriodSeconds: ptr.To(int64(1)),
-					ServiceAccountName:            sa,
-					HostNetwork:                   dp.HostNetwork,
-					Containers:                    cmdContainers,
-					Affinity: &corev1.Affinity{
-						NodeAffinity:    &dp.NodeAffinity,
-						PodAffinity:     &dp.PodAffinity,
-						PodAntiAffinity: &dp.PodAntiAffinity,
-					},
-				},
+		Spec: corev1.PodSpec{
+			TerminationGracePeriodSeconds: ptr.To(int64(1)),
+			ServiceAccountName:            sa,
+			HostNetwork:                   dp.HostNetwork,
+			Containers:                    cmdContainers,
+			ImagePullSecrets:              pullSecretRefs(),
+			Affinity: &corev1.Affinity{
+				NodeAffinity:    &dp.NodeAffinity,
+				PodAffinity:     &dp.PodAffinity,
+				PodAntiAffinity: &dp.PodAntiAffinity,
 			},
+			SchedulerName:     schedulerName,
+			Tolerations:       tolerations,
+			PriorityClassName: priorityClassName,
 		},
 	}
-	return dc.Create(context.TODO(), deployment, metav1.CreateOptions{})
+	if v := hugepagesVolume(); v != nil {
+		tpl.Spec.Volumes = []corev1.Volume{*v}
+	}
+	// A sandboxed runtime does not run in the network namespace of the host.
+	class := runtimeClassName
+	if dp.RuntimeClassName != "" {
+		class = dp.RuntimeClassName
+	}
+	if class != "" && !dp.HostNetwork {
+		tpl.Spec.RuntimeClassName = ptr.To(class)
+	}
+	return tpl
 }
 
 // GetPodNodeInfo collects the node information for a node running a pod with a specific label
@@ -1645,14 +1821,25 @@ func GetPodNodeInfo(c *kubernetes.Clientset, label string) (metrics.NodeInfo, er
 	if err != nil {
 		return info, fmt.Errorf("❌ Failure to capture pods: %v", err)
 	}
-	info.NodeName = pods.Items[0].Spec.NodeName
-	info.IP = pods.Items[0].Status.HostIP
+	info, err = PodNodeInfo(c, pods.Items[0])
+	if err != nil {
+		return info, err
+	}
+	log.Debugf("Machine with label %s is Running on %s with IP %s", label, info.NodeName, info.IP)
+	return info, nil
+}
+
+// PodNodeInfo returns the info of the node the pod runs on.
+func PodNodeInfo(c *kubernetes.Clientset, pod corev1.Pod) (metrics.NodeInfo, error) {
+	info := metrics.NodeInfo{
+		NodeName: pod.Spec.NodeName,
+		IP:       pod.Status.HostIP,
+	}
 	node, err := c.CoreV1().Nodes().Get(context.TODO(), info.NodeName, metav1.GetOptions{})
 	if err != nil {
 		return info, err
 	}
 	info.NodeSystemInfo = node.Status.NodeInfo
-	log.Debugf("Machine with label %s is Running on %s with IP %s", label, info.NodeName, info.IP)
 	return info, nil
 }
 
@@ -1752,14 +1939,19 @@ func DestroyService(client *kubernetes.Clientset, serv corev1.Service) error {
 
 // DestroyNamespace cleans up the namespace k8s-netperf created
 func DestroyNamespace(client *kubernetes.Clientset) error {
-	_, err := client.CoreV1().Namespaces().Get(context.TODO(), namespace, metav1.GetOptions{})
+	return DestroyNamespaceByName(client, namespace)
+}
+
+// DestroyNamespaceByName deletes the given namespace and waits for it to be gone.
+func DestroyNamespaceByName(client *kubernetes.Clientset, name string) error {
+	_, err := client.CoreV1().Namespaces().Get(context.TODO(), name, metav1.GetOptions{})
 	if err == nil {
-		log.Info("Cleaning resources created by k8s-netperf")
-		err = client.CoreV1().Namespaces().Delete(context.TODO(), namespace, metav1.DeleteOptions{})
+		log.Infof("Cleaning resources created by k8s-netperf in namespace %s", name)
+		err = client.CoreV1().Namespaces().Delete(context.TODO(), name, metav1.DeleteOptions{})
 		if err != nil {
 			return err
 		}
-		return waitForNamespaceDelete(client, namespace)
+		return waitForNamespaceDelete(client, name)
 	}
 	return nil
 }
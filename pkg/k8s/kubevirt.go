@@ -17,7 +17,7 @@ import (
 	"github.com/melbahja/goph"
 	"golang.org/x/crypto/ssh"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/resource"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -108,6 +108,10 @@ func createCommService(client *kubernetes.Clientset, label map[string]string, na
 		},
 	}
 	_, err := sc.Create(context.TODO(), service, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		log.Infof("♻️ Using existing service %s", name)
+		return nil
+	}
 	return err
 }
 
@@ -140,13 +144,13 @@ func exposeService(client *kubernetes.Clientset, dynamicClient *dynamic.DynamicC
 			},
 		},
 	}
-	route, err := dynamicClient.Resource(gvr).Namespace(namespace).Create(context.TODO(), route, metav1.CreateOptions{})
-	if err != nil {
+	_, err := dynamicClient.Resource(gvr).Namespace(namespace).Create(context.TODO(), route, metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
 		return "", fmt.Errorf("failed to create route: %v", err)
 	}
 	retrievedRoute, err := dynamicClient.Resource(gvr).Namespace(namespace).Get(context.TODO(), route.GetName(), metav1.GetOptions{})
 	if err != nil {
-		log.Fatalf("error retrieving route: %v", err)
+		return "", fmt.Errorf("error retrieving route: %v", err)
 	}
 	spec, ok := retrievedRoute.Object["spec"].(map[string]interface{})
 	if !ok {
@@ -162,49 +166,15 @@ func exposeService(client *kubernetes.Clientset, dynamicClient *dynamic.DynamicC
 // CreateVMClient takes in the affinity rules and deploys the VMI
 func CreateVMClient(kclient *kubevirtv1.KubevirtV1Client, client *kubernetes.Clientset,
 	dyn *dynamic.DynamicClient, name string, podAff *corev1.PodAntiAffinity, nodeAff *corev1.NodeAffinity, vmimage string, bridgeNetwork string, udn bool, udnPluginBinding string,
-	cudn bool, localnet bool, localnetNetwork string, sriovNetwork string, sockets uint32, cores uint32, threads uint32) (string, error) {
+	cudn bool, localnet bool, localnetNetwork string, sriovNetwork string, spec config.VMSpec,
+	tools string, toolsConfigMap string, access string, sshKey string) (string, error) {
 	log.Debugf("CreateVMClient: localnet=%v, localnetNetwork=%s", localnet, localnetNetwork)
 	label := map[string]string{
 		"app":  name,
 		"role": name,
 	}
-	dirname, err := os.UserHomeDir()
-	if err != nil {
-		return "", err
-	}
-	ssh, err := os.ReadFile(fmt.Sprintf("%s/.ssh/id_rsa.pub", dirname))
-	if err != nil {
-		return "", err
-	}
 	netData := "{}"
-	data := fmt.Sprintf(`#cloud-config
-users:
-  - name: fedora
-    groups: sudo
-    shell: /bin/bash
-    sudo: ['ALL=(ALL) NOPASSWD:ALL']
-    ssh_deletekeys: false
-    ssh_authorized_keys:
-      - %s
-chpasswd:
-  list: |
-    fedora:fedora
-  expire: False
-runcmd:
-  - export HOME=/home/fedora
-  - until dnf install -y --nodocs uperf iperf3 git ethtool automake gcc bc lksctp-tools-devel texinfo --enablerepo=*; do sleep 3; done
-  - git clone https://github.com/HewlettPackard/netperf.git
-  - cd netperf
-  - git reset --hard 3bc455b23f901dae377ca0a558e1e32aa56b31c4
-  - curl -o netperf.diff https://raw.githubusercontent.com/cloud-bulldozer/k8s-netperf/main/containers/netperf.diff
-  - git apply netperf.diff
-  - ./autogen.sh
-  - ./configure --enable-sctp=yes --enable-demo=yes
-  - make && make install
-  - cd
-  - curl -o /usr/bin/super-netperf https://raw.githubusercontent.com/cloud-bulldozer/k8s-netperf/main/containers/super-netperf
-  - chmod 0777 /usr/bin/super-netperf
-`, ssh)
+	data := vmUserData(sshKey, tools, false, access == VMAccessVSOCK)
 	interfaces := []v1.Interface{
 		{
 			Name: "default",
@@ -232,7 +202,7 @@ runcmd:
 			Name: "br-netperf",
 			NetworkSource: v1.NetworkSource{
 				Multus: &v1.MultusNetwork{
-					NetworkName: "netperf/br-netperf",
+					NetworkName: namespace + "/br-netperf",
 				},
 			},
 		})
@@ -273,7 +243,7 @@ ethernets:
 			Name: "secondary",
 			NetworkSource: v1.NetworkSource{
 				Multus: &v1.MultusNetwork{
-					NetworkName: namespace + "/" + CudnName,
+					NetworkName: namespace + "/" + CudnName(),
 				},
 			},
 		})
@@ -292,7 +262,7 @@ ethernets:
 			Name: "secondary-localnet",
 			NetworkSource: v1.NetworkSource{
 				Multus: &v1.MultusNetwork{
-					NetworkName: namespace + "/" + LocalnetCudnName,
+					NetworkName: namespace + "/" + LocalnetCudnName(),
 				},
 			},
 		})
@@ -311,15 +281,19 @@ ethernets:
 			Name: "sriov-netperf",
 			NetworkSource: v1.NetworkSource{
 				Multus: &v1.MultusNetwork{
-					NetworkName: namespace + "/" + SriovNadName,
+					NetworkName: namespace + "/" + SriovNadName(),
 				},
 			},
 		})
 	}
-	_, err = CreateVMI(kclient, name, label, b64.StdEncoding.EncodeToString([]byte(data)), *podAff, *nodeAff, vmimage, interfaces, networks, b64.StdEncoding.EncodeToString([]byte(netData)), sriovNetwork, sockets, cores, threads)
+	_, err := CreateVMI(kclient, name, label, b64.StdEncoding.EncodeToString([]byte(data)), *podAff, *nodeAff, vmimage, interfaces, networks, b64.StdEncoding.EncodeToString([]byte(netData)), sriovNetwork, spec, toolsConfigMap, nil, access == VMAccessVSOCK)
 	if err != nil {
 		return "", err
 	}
+	// ssh is tunneled through the KubeVirt API, nothing is exposed.
+	if tunneled(access) {
+		return "", nil
+	}
 	if strings.Contains(name, "host") {
 		err = createCommService(client, label, fmt.Sprintf("%s-svc", name))
 	} else {
@@ -339,50 +313,15 @@ ethernets:
 func CreateVMServer(client *kubevirtv1.KubevirtV1Client, name string, role string, podAff corev1.PodAntiAffinity,
 	nodeAff corev1.NodeAffinity, vmimage string, bridgeNetwork string, udn bool, udnPluginBinding string, cudn bool,
 	localnet bool, localnetNetwork string,
-	sriovNetwork string, sockets uint32, cores uint32, threads uint32) (*v1.VirtualMachineInstance, error) {
+	sriovNetwork string, spec config.VMSpec,
+	tools string, toolsConfigMap string, access string, sshKey string) (*v1.VirtualMachineInstance, error) {
 	log.Debugf("CreateVMServer: localnet=%v, localnetNetwork=%s", localnet, localnetNetwork)
 	label := map[string]string{
 		"app":  name,
 		"role": role,
 	}
 	netData := "{}"
-	dirname, err := os.UserHomeDir()
-	if err != nil {
-		return nil, err
-	}
-	ssh, err := os.ReadFile(fmt.Sprintf("%s/.ssh/id_rsa.pub", dirname))
-	if err != nil {
-		return nil, err
-	}
-	data := fmt.Sprintf(`#cloud-config
-users:
-  - name: fedora
-    ssh_deletekeys: false
-    groups: sudo
-    shell: /bin/bash
-    sudo: ['ALL=(ALL) NOPASSWD:ALL']
-    ssh_authorized_keys:
-      - %s
-chpasswd:
-  list: |
-    fedora:fedora
-  expire: False
-runcmd:
-  - export HOME=/home/fedora
-  - until dnf install -y --nodocs uperf iperf3 git ethtool automake gcc bc lksctp-tools-devel texinfo --enablerepo=*; do sleep 3; done
-  - git clone https://github.com/HewlettPackard/netperf.git
-  - cd netperf
-  - git reset --hard 3bc455b23f901dae377ca0a558e1e32aa56b31c4
-  - curl -o netperf.diff https://raw.githubusercontent.com/cloud-bulldozer/k8s-netperf/main/containers/netperf.diff
-  - git apply netperf.diff 
-  - ./autogen.sh 
-  - ./configure --enable-sctp=yes --enable-demo=yes 
-  - make && make install
-  - cd
-  - uperf -s -v -P %d &
-  - iperf3 -s -p %d &
-  - netserver &
-`, string(ssh), UperfServerCtlPort, IperfServerCtlPort)
+	data := vmUserData(sshKey, tools, true, access == VMAccessVSOCK)
 	interfaces := []v1.Interface{
 		{
 			Name: "default",
@@ -410,7 +349,7 @@ runcmd:
 			Name: "br-netperf",
 			NetworkSource: v1.NetworkSource{
 				Multus: &v1.MultusNetwork{
-					NetworkName: "netperf/br-netperf",
+					NetworkName: namespace + "/br-netperf",
 				},
 			},
 		})
@@ -451,7 +390,7 @@ ethernets:
 			Name: "secondary",
 			NetworkSource: v1.NetworkSource{
 				Multus: &v1.MultusNetwork{
-					NetworkName: namespace + "/" + CudnName,
+					NetworkName: namespace + "/" + CudnName(),
 				},
 			},
 		})
@@ -470,7 +409,7 @@ ethernets:
 			Name: "secondary-localnet",
 			NetworkSource: v1.NetworkSource{
 				Multus: &v1.MultusNetwork{
-					NetworkName: namespace + "/" + LocalnetCudnName,
+					NetworkName: namespace + "/" + LocalnetCudnName(),
 				},
 			},
 		})
@@ -489,26 +428,70 @@ ethernets:
 			Name: "sriov-netperf",
 			NetworkSource: v1.NetworkSource{
 				Multus: &v1.MultusNetwork{
-					NetworkName: namespace + "/" + SriovNadName,
+					NetworkName: namespace + "/" + SriovNadName(),
 				},
 			},
 		})
 	}
-	return CreateVMI(client, name, label, b64.StdEncoding.EncodeToString([]byte(data)), podAff, nodeAff, vmimage, interfaces, networks, b64.StdEncoding.EncodeToString([]byte(netData)), sriovNetwork, sockets, cores, threads)
+	// The probe runs from the pod network, which the VM does not see with a primary UDN.
+	var readiness *v1.Probe
+	if !udn {
+		readiness = serverReadinessProbe()
+	}
+	return CreateVMI(client, name, label, b64.StdEncoding.EncodeToString([]byte(data)), podAff, nodeAff, vmimage, interfaces, networks, b64.StdEncoding.EncodeToString([]byte(netData)), sriovNetwork, spec, toolsConfigMap, readiness, access == VMAccessVSOCK)
 }
 
 // CreateVMI creates the desired Virtual Machine instance with the cloud-init config with affinity.
+// The tools ConfigMap, when set, is attached as a disk, and the readiness probe, when set, reports
+// when the VMI is ready. vsock attaches a VSOCK device to the VMI.
 func CreateVMI(client *kubevirtv1.KubevirtV1Client, name string, label map[string]string, b64data string, podAff corev1.PodAntiAffinity,
 	nodeAff corev1.NodeAffinity, vmimage string, interfaces []v1.Interface, networks []v1.Network, netDatab64 string,
-	sriovNetwork string, sockets uint32, cores uint32, threads uint32) (*v1.VirtualMachineInstance, error) {
+	sriovNetwork string, spec config.VMSpec, toolsConfigMap string, readiness *v1.Probe, vsock bool) (*v1.VirtualMachineInstance, error) {
 	delSeconds := int64(0)
-	mutliQ := true
-	resourceRequests := corev1.ResourceList{
-		corev1.ResourceMemory: resource.MustParse("4096Mi"),
-		corev1.ResourceCPU:    resource.MustParse("500m"),
+	mutliQ := spec.MultiQueue
+	cpu, memory, machine := vmDomain(spec)
+	existing, err := client.VirtualMachineInstances(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err == nil {
+		// Reuse the VMI of an interrupted run, as long as it is still running.
+		if existing.Status.Phase != v1.Running {
+			return existing, fmt.Errorf("VMI %s already exists in phase %s, remove it with k8s-netperf cleanup", name, existing.Status.Phase)
+		}
+		log.Infof("♻️ Using existing VMI %s", name)
+		return existing, nil
+	}
+	disks := []v1.Disk{
+		{
+			Name: "disk0",
+			DiskDevice: v1.DiskDevice{
+				Disk: &v1.DiskTarget{
+					Bus: "virtio",
+				},
+			},
+		},
+	}
+	volumes := []v1.Volume{
+		{
+			Name: "disk0",
+			VolumeSource: v1.VolumeSource{
+				ContainerDisk: &v1.ContainerDiskSource{
+					Image: vmimage,
+				},
+			},
+		},
+		{
+			Name: "cloudinit",
+			VolumeSource: v1.VolumeSource{
+				CloudInitNoCloud: &v1.CloudInitNoCloudSource{
+					UserDataBase64:    b64data,
+					NetworkDataBase64: netDatab64,
+				},
+			},
+		},
 	}
-	if sriovNetwork != "" {
-		resourceRequests[corev1.ResourceName("openshift.io/"+sriovNetwork)] = resource.MustParse("1")
+	if toolsConfigMap != "" {
+		disk, volume := vmToolsDisk(toolsConfigMap)
+		disks = append(disks, disk)
+		volumes = append(volumes, volume)
 	}
 	vmi, err := client.VirtualMachineInstances(namespace).Create(context.TODO(), &v1.VirtualMachineInstance{
 		TypeMeta: metav1.TypeMeta{
@@ -526,50 +509,23 @@ func CreateVMI(client *kubevirtv1.KubevirtV1Client, name string, label map[strin
 				NodeAffinity:    &nodeAff,
 			},
 			TerminationGracePeriodSeconds: &delSeconds,
+			Tolerations:                   tolerations,
+			PriorityClassName:             priorityClassName,
 			Domain: v1.DomainSpec{
-				Resources: v1.ResourceRequirements{
-					Requests: resourceRequests,
-				},
-				CPU: &v1.CPU{
-					Sockets: sockets,
-					Cores:   cores,
-					Threads: threads,
-				},
+				Resources: vmResources(spec, sriovNetwork),
+				CPU:       cpu,
+				Memory:    memory,
+				Machine:   machine,
 				Devices: v1.Devices{
 					NetworkInterfaceMultiQueue: &mutliQ,
-					Disks: []v1.Disk{
-						{
-							Name: "disk0",
-							DiskDevice: v1.DiskDevice{
-								Disk: &v1.DiskTarget{
-									Bus: "virtio",
-								},
-							},
-						},
-					},
-					Interfaces: interfaces,
-				},
-			},
-			Networks: networks,
-			Volumes: []v1.Volume{
-				{
-					Name: "disk0",
-					VolumeSource: v1.VolumeSource{
-						ContainerDisk: &v1.ContainerDiskSource{
-							Image: vmimage,
-						},
-					},
-				},
-				{
-					Name: "cloudinit",
-					VolumeSource: v1.VolumeSource{
-						CloudInitNoCloud: &v1.CloudInitNoCloudSource{
-							UserDataBase64:    b64data,
-							NetworkDataBase64: netDatab64,
-						},
-					},
+					AutoattachVSOCK:            &vsock,
+					Disks:                      disks,
+					Interfaces:                 interfaces,
 				},
 			},
+			Networks:       networks,
+			Volumes:        volumes,
+			ReadinessProbe: readiness,
 		},
 	}, metav1.CreateOptions{})
 	if err != nil {
@@ -578,7 +534,24 @@ func CreateVMI(client *kubevirtv1.KubevirtV1Client, name string, label map[strin
 	return vmi, nil
 }
 
-// WaitForVMI will wait until the resource is in Running state.
+// KubeVirtVersion returns the version of KubeVirt deployed in the cluster, from the kubevirt CR.
+func KubeVirtVersion(client *kubevirtv1.KubevirtV1Client) (string, error) {
+	kvs, err := client.KubeVirts(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return "", err
+	}
+	for _, kv := range kvs.Items {
+		if kv.Status.ObservedKubeVirtVersion != "" {
+			return kv.Status.ObservedKubeVirtVersion, nil
+		}
+		if kv.Status.OperatorVersion != "" {
+			return kv.Status.OperatorVersion, nil
+		}
+	}
+	return "", fmt.Errorf("no kubevirt CR reports a KubeVirt version")
+}
+
+// WaitForVMI will wait until the resource is in Running state, and ready when it has a readiness probe.
 func WaitForVMI(client *kubevirtv1.KubevirtV1Client, name string) error {
 	log.Infof("⏰ Wating for VMI (%s) to be in state running", name)
 	vmw, err := client.VirtualMachineInstances(namespace).Watch(context.TODO(), metav1.ListOptions{})
@@ -593,7 +566,7 @@ func WaitForVMI(client *kubevirtv1.KubevirtV1Client, name string) error {
 		}
 		if d.Name == name {
 			log.Debugf("Found in state (%s)", d.Status.Phase)
-			if d.Status.Phase == "Running" {
+			if d.Status.Phase == "Running" && (d.Spec.ReadinessProbe == nil || vmiReady(d)) {
 				return nil
 			}
 		}
@@ -601,6 +574,16 @@ func WaitForVMI(client *kubevirtv1.KubevirtV1Client, name string) error {
 	return nil
 }
 
+// vmiReady returns whether the readiness probe of the VMI succeeds.
+func vmiReady(vmi *v1.VirtualMachineInstance) bool {
+	for _, c := range vmi.Status.Conditions {
+		if c.Type == v1.VirtualMachineInstanceReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
 // VirtctlClient implements VMExecutor interface using virtctl ssh
 type VirtctlClient struct {
 	vmName    string
@@ -662,13 +645,25 @@ func (v *VirtctlClient) Close() error {
 
 // ConfigureVMSriovIP extracts the SR-IOV IP from the virt-launcher pod's network-status
 // annotation and configures it inside the guest VM via virtctl ssh.
-func ConfigureVMSriovIP(vmName string, pod corev1.Pod) error {
+func ConfigureVMSriovIP(perf *config.PerfScenarios, vmName string, pod corev1.Pod) error {
 	ip, err := ExtractSriovIp(pod)
 	if err != nil {
 		return fmt.Errorf("failed to extract SR-IOV IP for VM %s: %v", vmName, err)
 	}
 	log.Infof("Configuring SR-IOV IP %s/24 on VM %s", ip, vmName)
-	vc := NewVirtctlClient(vmName, namespace)
+	var vc config.VMExecutor = NewVirtctlClient(vmName, namespace)
+	if tunneled(perf.VMAccess) {
+		c, err := TunnelConnect(perf, vmName)
+		if err != nil {
+			return fmt.Errorf("SSH not available on VM %s: %v", vmName, err)
+		}
+		vc = &SSHClientWrapper{Client: c}
+		defer func() {
+			if err := vc.Close(); err != nil {
+				log.Warnf("Error closing VM client: %v", err)
+			}
+		}()
+	}
 	// Wait for cloud-init to finish and SSH to be available
 	for i := 0; i < retry; i++ {
 		_, err = vc.Run("echo ready")
@@ -707,6 +702,13 @@ func ConnectToVM(conf *config.PerfScenarios) (config.VMExecutor, error) {
 	if conf.UseVirtctl && conf.VMName != "" {
 		log.Debugf("Connecting to VM %s using virtctl", conf.VMName)
 		return NewVirtctlClient(conf.VMName, namespace), nil
+	} else if tunneled(conf.VMAccess) {
+		log.Debugf("Connecting to VM %s using SSH over %s", conf.VMName, conf.VMAccess)
+		sshClient, err := TunnelConnect(conf, conf.VMName)
+		if err != nil {
+			return nil, err
+		}
+		return &SSHClientWrapper{Client: sshClient}, nil
 	} else {
 		log.Debugf("Connecting to VM %s using SSH", conf.VMHost)
 		sshClient, err := SSHConnect(conf)
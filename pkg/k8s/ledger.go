@@ -0,0 +1,132 @@
+package k8s
+
+import (
+	"fmt"
+	"sync"
+
+	log "github.com/cloud-bulldozer/k8s-netperf/pkg/logging"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Ledger records the cluster resources created by a run, so that they can be
+// torn down on any exit path, in the reverse order of their creation.
+type Ledger struct {
+	mu        sync.Mutex
+	resources []ledgerEntry
+	closed    bool
+	// creating is held for reading while a resource is created, Teardown waits for
+	// the creations in progress before taking its snapshot of the resources.
+	creating sync.RWMutex
+	once     sync.Once
+	err      error
+}
+
+type ledgerEntry struct {
+	kind    string
+	name    string
+	destroy func() error
+}
+
+// Record adds a resource to the ledger. It should be called before the resource
+// is created, so that a partially created resource is torn down as well. Once the
+// teardown started, it returns an error and the resource must not be created.
+func (l *Ledger) Record(kind, name string, destroy func() error) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return fmt.Errorf("not creating %s %s, the run is being torn down", kind, name)
+	}
+	for _, r := range l.resources {
+		if r.kind == kind && r.name == name {
+			return nil
+		}
+	}
+	l.resources = append(l.resources, ledgerEntry{kind: kind, name: name, destroy: destroy})
+	return nil
+}
+
+// Create records a resource and creates it. A teardown which starts meanwhile waits
+// for the creation to complete, so that the resource is torn down as well.
+func (l *Ledger) Create(kind, name string, destroy, create func() error) error {
+	l.creating.RLock()
+	defer l.creating.RUnlock()
+	if err := l.Record(kind, name, destroy); err != nil {
+		return err
+	}
+	return create()
+}
+
+// Resources returns the kind/name of the recorded resources, in creation order.
+func (l *Ledger) Resources() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var res []string
+	for _, r := range l.resources {
+		res = append(res, fmt.Sprintf("%s/%s", r.kind, r.name))
+	}
+	return res
+}
+
+// Teardown destroys the recorded resources, most recent first. Resources which
+// no longer exist are skipped. Teardown only runs once, concurrent and later
+// calls wait for it to complete and return the same error.
+func (l *Ledger) Teardown() error {
+	l.once.Do(func() {
+		l.creating.Lock()
+		l.mu.Lock()
+		l.closed = true
+		resources := l.resources
+		l.mu.Unlock()
+		l.creating.Unlock()
+		failed := 0
+		for i := len(resources) - 1; i >= 0; i-- {
+			r := resources[i]
+			log.Debugf("♻️ Deleting %s %s", r.kind, r.name)
+			err := r.destroy()
+			if err != nil && !apierrors.IsNotFound(err) {
+				log.Errorf("Unable to delete %s %s: %v", r.kind, r.name, err)
+				failed++
+			}
+		}
+		if failed > 0 {
+			l.err = fmt.Errorf("unable to delete %d of the %d resources created by k8s-netperf", failed, len(resources))
+		}
+	})
+	return l.err
+}
+
+// CreateNamespace creates the namespace of the run with create, and records it.
+func (l *Ledger) CreateNamespace(client *kubernetes.Clientset, create func() error) error {
+	return l.Create("Namespace", namespace, func() error {
+		return DestroyNamespace(client)
+	}, create)
+}
+
+// CreateCUdn creates a ClusterUserDefinedNetwork with create, and records it.
+func (l *Ledger) CreateCUdn(dyn *dynamic.DynamicClient, name string, create func() error) error {
+	return l.Create("ClusterUserDefinedNetwork", name, func() error {
+		return DestroyCUdn(dyn, name)
+	}, create)
+}
+
+// CreateSriov creates the SriovNetworkNodePolicy and SriovNetwork with create, and records them.
+func (l *Ledger) CreateSriov(dyn *dynamic.DynamicClient, create func() error) error {
+	return l.Create("SriovNetworkNodePolicy", SriovPolicyName(), func() error {
+		return DestroySriovResources(dyn)
+	}, create)
+}
+
+// UpdateSriovOperatorConfig updates the SriovOperatorConfig, see DeploySriovOperatorConfig,
+// and records it, so that its previous node selector and drain settings are restored.
+func (l *Ledger) UpdateSriovOperatorConfig(dyn *dynamic.DynamicClient, nodeRole string) error {
+	var prev map[string]interface{}
+	return l.Create("SriovOperatorConfig", "default", func() error {
+		return restoreSriovOperatorConfig(dyn, prev)
+	}, func() error {
+		var err error
+		prev, err = DeploySriovOperatorConfig(dyn, nodeRole)
+		return err
+	})
+}
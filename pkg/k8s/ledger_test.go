@@ -0,0 +1,88 @@
+package k8s
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestLedgerTeardown(t *testing.T) {
+	l := &Ledger{}
+	var order []string
+	destroy := func(name string, err error) func() error {
+		return func() error {
+			order = append(order, name)
+			return err
+		}
+	}
+	for _, r := range []struct {
+		kind, name string
+		destroy    func() error
+	}{
+		{"Namespace", "netperf", destroy("netperf", nil)},
+		{"ClusterUserDefinedNetwork", "cudn", destroy("cudn", apierrors.NewNotFound(schema.GroupResource{}, "cudn"))},
+		{"ClusterUserDefinedNetwork", "cudn", destroy("duplicate", nil)},
+		{"SriovNetworkNodePolicy", "policy", destroy("policy", fmt.Errorf("forbidden"))},
+	} {
+		if err := l.Record(r.kind, r.name, r.destroy); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got, want := l.Resources(), []string{"Namespace/netperf", "ClusterUserDefinedNetwork/cudn", "SriovNetworkNodePolicy/policy"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Resources() = %v, want %v", got, want)
+	}
+	if err := l.Teardown(); err == nil {
+		t.Fatal("Teardown returned no error, want the SR-IOV failure")
+	}
+	if want := []string{"policy", "cudn", "netperf"}; !reflect.DeepEqual(order, want) {
+		t.Fatalf("Teardown destroyed %v, want %v", order, want)
+	}
+	// Teardown only runs once.
+	if err := l.Teardown(); err == nil || len(order) != 3 {
+		t.Fatalf("second Teardown destroyed %v, err %v", order, err)
+	}
+}
+
+func TestLedgerTeardownDuringCreate(t *testing.T) {
+	l := &Ledger{}
+	created := make(chan struct{})
+	release := make(chan struct{})
+	destroyed := false
+	go func() {
+		_ = l.Create("ClusterUserDefinedNetwork", "cudn", func() error {
+			destroyed = true
+			return nil
+		}, func() error {
+			close(created)
+			<-release
+			return nil
+		})
+	}()
+	<-created
+	done := make(chan error)
+	go func() { done <- l.Teardown() }()
+	select {
+	case <-done:
+		t.Fatal("Teardown did not wait for the creation in progress")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if !destroyed {
+		t.Fatal("the resource created during the teardown was not destroyed")
+	}
+	err := l.Create("Namespace", "netperf", func() error { return nil }, func() error {
+		t.Fatal("a resource was created after the teardown")
+		return nil
+	})
+	if err == nil {
+		t.Fatal("Create after Teardown returned no error")
+	}
+}
@@ -0,0 +1,105 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/config"
+	log "github.com/cloud-bulldozer/k8s-netperf/pkg/logging"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+const meshRole = "mesh"
+
+// DeployMesh deploys a DaemonSet on every ready and schedulable node matching the
+// selector, with a server for each requested driver. Each pod is the server of the
+// tests towards its node and the client of the tests from its node.
+// returns the ready mesh pods, by node name.
+func DeployMesh(client *kubernetes.Clientset, s *config.PerfScenarios, selector string) (map[string]corev1.Pod, error) {
+	sel := nodeSelection{selector: selector}
+	nodes, err := sel.eligibleNodes(client)
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) < 2 {
+		return nil, fmt.Errorf("the mesh needs at least 2 nodes, %q matches %d", selector, len(nodes))
+	}
+	commands, err := serverCommands(s)
+	if err != nil {
+		return nil, err
+	}
+	dp := DeploymentParams{
+		Name:       meshRole,
+		Namespace:  namespace,
+		Image:      netperfImage,
+		Labels:     map[string]string{"role": meshRole},
+		Commands:   commands,
+		Port:       NetperfServerCtlPort,
+		Privileged: s.Privileged,
+	}
+	// Only the ready and schedulable nodes, so the DaemonSet does not wait for a pod which never starts.
+	dp.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{
+		NodeSelectorTerms: []corev1.NodeSelectorTerm{
+			{
+				MatchFields: []corev1.NodeSelectorRequirement{
+					{Key: "metadata.name", Operator: corev1.NodeSelectorOpIn, Values: nodes},
+				},
+			},
+		},
+	}
+	log.Infof("🚀 Starting the mesh DaemonSet on %d nodes in namespace: %s", len(nodes), namespace)
+	ds := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: dp.Name,
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: dp.Labels,
+			},
+			Template: podTemplate(dp),
+		},
+	}
+	_, err = client.AppsV1().DaemonSets(namespace).Create(context.TODO(), ds, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("😥 Unable to create the mesh DaemonSet: %v", err)
+	}
+	if err := waitForDaemonSet(client, dp.Name, len(nodes)); err != nil {
+		return nil, err
+	}
+	pods, err := GetPods(client, labels.Set(dp.Labels).String())
+	if err != nil {
+		return nil, err
+	}
+	mesh := make(map[string]corev1.Pod)
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == corev1.PodRunning {
+			mesh[pod.Spec.NodeName] = pod
+		}
+	}
+	return mesh, nil
+}
+
+// waitForDaemonSet waits until the pods of the DaemonSet are ready on the expected number of nodes.
+func waitForDaemonSet(c *kubernetes.Clientset, name string, expected int) error {
+	log.Infof("⏰ Checking for %s Pods to become ready...", name)
+	dw, err := c.AppsV1().DaemonSets(namespace).Watch(context.TODO(), metav1.ListOptions{FieldSelector: "metadata.name=" + name})
+	if err != nil {
+		return err
+	}
+	defer dw.Stop()
+	for event := range dw.ResultChan() {
+		d, ok := event.Object.(*appsv1.DaemonSet)
+		if !ok {
+			return fmt.Errorf("unable to watch DaemonSet %s", name)
+		}
+		log.Debugf("DaemonSet %s has %d/%d pods ready", name, d.Status.NumberReady, expected)
+		if int(d.Status.NumberReady) >= expected {
+			return nil
+		}
+	}
+	return fmt.Errorf("❌ DaemonSet had issues")
+}
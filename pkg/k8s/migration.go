@@ -0,0 +1,109 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/config"
+	log "github.com/cloud-bulldozer/k8s-netperf/pkg/logging"
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/metrics"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	v1 "kubevirt.io/api/core/v1"
+)
+
+// migrationTimeout bounds how long a live migration may take.
+const migrationTimeout = 10 * time.Minute
+
+// vmiName returns the name of the server or client VMI.
+func vmiName(perf *config.PerfScenarios, role string) string {
+	if role == config.MigrateClient {
+		return perf.VMName
+	}
+	return vmServerRole
+}
+
+// MigrateVMI live-migrates the server or client VMI, and waits for the migration to finish.
+// returns the migration state of the VMI, with the source and target nodes.
+func MigrateVMI(perf *config.PerfScenarios, role string) (*v1.VirtualMachineInstanceMigrationState, error) {
+	name := vmiName(perf, role)
+	vmi, err := perf.KClient.VirtualMachineInstances(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if !vmi.IsMigratable() {
+		for _, c := range vmi.Status.Conditions {
+			if c.Type == v1.VirtualMachineInstanceIsMigratable {
+				return nil, fmt.Errorf("the VMI %s is not live-migratable: %s", name, c.Message)
+			}
+		}
+		return nil, fmt.Errorf("the VMI %s is not live-migratable", name)
+	}
+	mig, err := perf.KClient.VirtualMachineInstanceMigrations(namespace).Create(context.TODO(), &v1.VirtualMachineInstanceMigration{
+		ObjectMeta: metav1.ObjectMeta{GenerateName: name + "-migration-"},
+		Spec:       v1.VirtualMachineInstanceMigrationSpec{VMIName: name},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to migrate the VMI %s: %v", name, err)
+	}
+	log.Infof("🚚 Live-migrating the VMI %s from %s", name, vmi.Status.NodeName)
+	ctx, cancel := context.WithTimeout(context.Background(), migrationTimeout)
+	defer cancel()
+	w, err := perf.KClient.VirtualMachineInstanceMigrations(namespace).Watch(ctx, metav1.ListOptions{FieldSelector: "metadata.name=" + mig.Name})
+	if err != nil {
+		return nil, err
+	}
+	defer w.Stop()
+	for event := range w.ResultChan() {
+		m, ok := event.Object.(*v1.VirtualMachineInstanceMigration)
+		if !ok || !m.IsFinal() {
+			continue
+		}
+		vmi, err := perf.KClient.VirtualMachineInstances(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		if m.Status.Phase == v1.MigrationFailed {
+			return vmi.Status.MigrationState, fmt.Errorf("the migration of the VMI %s failed", name)
+		}
+		log.Infof("🚚 VMI %s migrated to %s", name, vmi.Status.NodeName)
+		return vmi.Status.MigrationState, nil
+	}
+	return nil, fmt.Errorf("the migration of the VMI %s did not finish within %s", name, migrationTimeout)
+}
+
+// RefreshVMPods points the scenario at the virt-launcher pods the server and client VMIs run
+// in, and their nodes, which a live migration changes.
+func RefreshVMPods(perf *config.PerfScenarios) error {
+	vms := []struct {
+		name string
+		pods *corev1.PodList
+		info *metrics.NodeInfo
+	}{
+		{vmServerRole, &perf.VMServer, &perf.ServerNodeInfo},
+		{perf.VMName, &perf.VMClientAcross, &perf.ClientNodeInfo},
+	}
+	for _, vm := range vms {
+		vmi, err := perf.KClient.VirtualMachineInstances(namespace).Get(context.TODO(), vm.name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		pods, err := GetPods(perf.ClientSet, fmt.Sprintf("app=%s", vm.name))
+		if err != nil {
+			return err
+		}
+		// The source pod of a migration may still be running.
+		for _, pod := range pods.Items {
+			if pod.Spec.NodeName != vmi.Status.NodeName || pod.DeletionTimestamp != nil {
+				continue
+			}
+			*vm.pods = corev1.PodList{Items: []corev1.Pod{pod}}
+			if info, err := PodNodeInfo(perf.ClientSet, pod); err == nil {
+				*vm.info = info
+			}
+			break
+		}
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
+)
+
+// namespacePrefix is the prefix of the namespaces k8s-netperf creates by default.
+const namespacePrefix = "netperf"
+
+// managedByLabel marks the namespaces created by k8s-netperf, for k8s-netperf cleanup --all.
+const managedByLabel = "app.kubernetes.io/managed-by"
+
+// RunUUIDAnnotation records the UUID of the run which owns the namespace.
+const RunUUIDAnnotation = "k8s-netperf.io/uuid"
+
+// namespace the SUT is deployed in, see SetNamespace.
+var namespace = namespacePrefix
+
+// SetNamespace sets the namespace the SUT is deployed in and cleaned up from.
+func SetNamespace(ns string) error {
+	if errs := validation.IsDNS1123Label(ns); len(errs) > 0 {
+		return fmt.Errorf("invalid namespace %q: %s", ns, strings.Join(errs, ", "))
+	}
+	namespace = ns
+	return nil
+}
+
+// Namespace returns the namespace the SUT is deployed in.
+func Namespace() string {
+	return namespace
+}
+
+// NamespaceForRun returns the default namespace of the run with the given UUID,
+// netperf followed by the first 8 characters of the UUID, so that concurrent
+// runs do not collide and a resumed run finds its SUT.
+func NamespaceForRun(uuid string) string {
+	var b strings.Builder
+	for _, c := range strings.ToLower(uuid) {
+		if b.Len() == 8 {
+			break
+		}
+		if (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') {
+			b.WriteRune(c)
+		}
+	}
+	if b.Len() == 0 {
+		return namespacePrefix
+	}
+	return namespacePrefix + "-" + b.String()
+}
+
+// CudnName returns the name of the secondary C-UDN of the run, named after its namespace,
+// like the other cluster-scoped resources, so that concurrent runs do not collide.
+func CudnName() string {
+	return "cudn-secondary-" + namespace
+}
+
+// LocalnetCudnName returns the name of the localnet CUDN of the run.
+func LocalnetCudnName() string {
+	return "cudn-localnet-" + namespace
+}
+
+// SriovNadName returns the name of the SriovNetwork of the run, and of the
+// NetworkAttachmentDefinition it renders in the namespace.
+func SriovNadName() string {
+	return "sriov-" + namespace
+}
+
+// SriovPolicyName returns the name of the SriovNetworkNodePolicy of the run.
+func SriovPolicyName() string {
+	return "sriov-" + namespace + "-policy"
+}
+
+// namespaceMeta returns the metadata of the namespace created for the run with the given UUID.
+func namespaceMeta(uuid string, labels map[string]string) metav1.ObjectMeta {
+	labels[managedByLabel] = netperfNamespaceLabel
+	return metav1.ObjectMeta{
+		Name:        namespace,
+		Labels:      labels,
+		Annotations: map[string]string{RunUUIDAnnotation: uuid},
+	}
+}
+
+// resuming is set when the run resumes an interrupted run, see SetResuming.
+var resuming bool
+
+// SetResuming sets whether the run resumes an interrupted run, and reuses its SUT.
+func SetResuming(r bool) {
+	resuming = r
+}
+
+// checkNamespaceOwner returns an error when the namespace was not created by the run with
+// the given UUID, as its teardown deletes the namespace.
+func checkNamespaceOwner(ns *corev1.Namespace, uuid string) error {
+	owner, ok := ns.Annotations[RunUUIDAnnotation]
+	if !ok {
+		return fmt.Errorf("😥 Namespace %s was not created by k8s-netperf, pick another one with --namespace, or annotate it with %s=<uuid> and pass --uuid to hand it over to the run", ns.Name, RunUUIDAnnotation)
+	}
+	if owner != uuid {
+		return fmt.Errorf("😥 Namespace %s is in use by run %s, pick another one with --namespace or remove it with k8s-netperf cleanup --namespace %s", ns.Name, owner, ns.Name)
+	}
+	return nil
+}
+
+// CheckNamespace returns an error when the namespace exists and was not created by the
+// run with the given UUID.
+func CheckNamespace(client *kubernetes.Clientset, uuid string) error {
+	ns, err := client.CoreV1().Namespaces().Get(context.TODO(), namespace, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("unable to get namespace %s: %v", namespace, err)
+	}
+	return checkNamespaceOwner(ns, uuid)
+}
+
+// ManagedNamespaces returns the namespaces created by k8s-netperf.
+func ManagedNamespaces(client *kubernetes.Clientset) ([]string, error) {
+	nsl, err := client.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{LabelSelector: managedByLabel + "=" + netperfNamespaceLabel})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list the k8s-netperf namespaces: %v", err)
+	}
+	var names []string
+	for _, ns := range nsl.Items {
+		names = append(names, ns.Name)
+	}
+	return names, nil
+}
@@ -0,0 +1,48 @@
+package k8s
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNamespaceForRun(t *testing.T) {
+	tests := []struct {
+		uuid string
+		want string
+	}{
+		{"2F1C6A3E-9b1d-4c55-8a0e-1f2d3c4b5a69", "netperf-2f1c6a3e"},
+		{"ci-42", "netperf-ci42"},
+		{"", "netperf"},
+		{"___", "netperf"},
+	}
+	for _, tt := range tests {
+		got := NamespaceForRun(tt.uuid)
+		if got != tt.want {
+			t.Fatalf("NamespaceForRun(%q) = %q, want %q", tt.uuid, got, tt.want)
+		}
+		if err := SetNamespace(got); err != nil {
+			t.Fatalf("NamespaceForRun(%q) returned an invalid namespace: %v", tt.uuid, err)
+		}
+	}
+	if err := SetNamespace("Not_A_Namespace"); err == nil {
+		t.Fatalf("expected an error for an invalid namespace")
+	}
+	if got := Namespace(); got != "netperf" {
+		t.Fatalf("an invalid namespace must not be set, got %q", got)
+	}
+}
+
+func TestCheckNamespaceOwner(t *testing.T) {
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "netperf-a", Annotations: map[string]string{RunUUIDAnnotation: "a"}}}
+	if err := checkNamespaceOwner(ns, "a"); err != nil {
+		t.Fatalf("the namespace of the same run must be reused: %v", err)
+	}
+	if err := checkNamespaceOwner(ns, "b"); err == nil {
+		t.Fatalf("expected an error for the namespace of another run")
+	}
+	if err := checkNamespaceOwner(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}}, "b"); err == nil {
+		t.Fatalf("expected an error for a namespace not created by k8s-netperf")
+	}
+}
@@ -0,0 +1,191 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/config"
+	log "github.com/cloud-bulldozer/k8s-netperf/pkg/logging"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/client-go/kubernetes"
+)
+
+// nodeSelection pins the client or the server to a node, or to the nodes matching a label selector.
+type nodeSelection struct {
+	node     string
+	selector string
+}
+
+func clientSelection(s *config.PerfScenarios) nodeSelection {
+	return nodeSelection{node: s.ClientNode, selector: s.ClientNodeSelector}
+}
+
+func serverSelection(s *config.PerfScenarios) nodeSelection {
+	return nodeSelection{node: s.ServerNode, selector: s.ServerNodeSelector}
+}
+
+func (n nodeSelection) isSet() bool {
+	return n.node != "" || n.selector != ""
+}
+
+// String describes the selection, as reported in the node info of the results.
+func (n nodeSelection) String() string {
+	if n.node != "" {
+		return "node=" + n.node
+	}
+	if n.selector != "" {
+		return "selector=" + n.selector
+	}
+	return ""
+}
+
+// nodeSelector returns the scheduling requirement matching the selected nodes.
+func (n nodeSelection) nodeSelector() (*corev1.NodeSelector, error) {
+	if n.node != "" {
+		return &corev1.NodeSelector{
+			NodeSelectorTerms: []corev1.NodeSelectorTerm{
+				{
+					MatchFields: []corev1.NodeSelectorRequirement{
+						{Key: "metadata.name", Operator: corev1.NodeSelectorOpIn, Values: []string{n.node}},
+					},
+				},
+			},
+		}, nil
+	}
+	sel, err := labels.Parse(n.selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid node selector %q: %v", n.selector, err)
+	}
+	reqs, _ := sel.Requirements()
+	var exprs []corev1.NodeSelectorRequirement
+	for _, r := range reqs {
+		var op corev1.NodeSelectorOperator
+		switch r.Operator() {
+		case selection.In, selection.Equals, selection.DoubleEquals:
+			op = corev1.NodeSelectorOpIn
+		case selection.NotIn, selection.NotEquals:
+			op = corev1.NodeSelectorOpNotIn
+		case selection.Exists:
+			op = corev1.NodeSelectorOpExists
+		case selection.DoesNotExist:
+			op = corev1.NodeSelectorOpDoesNotExist
+		case selection.GreaterThan:
+			op = corev1.NodeSelectorOpGt
+		case selection.LessThan:
+			op = corev1.NodeSelectorOpLt
+		default:
+			return nil, fmt.Errorf("unsupported operator %s in node selector %q", r.Operator(), n.selector)
+		}
+		exprs = append(exprs, corev1.NodeSelectorRequirement{Key: r.Key(), Operator: op, Values: r.Values().List()})
+	}
+	return &corev1.NodeSelector{
+		NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: exprs}},
+	}, nil
+}
+
+// pin replaces the node affinity with the selection, when set. The zone and
+// netperf=client/server preferences no longer apply to a pinned client or server.
+func (n nodeSelection) pin(aff *corev1.NodeAffinity) error {
+	if !n.isSet() {
+		return nil
+	}
+	ns, err := n.nodeSelector()
+	if err != nil {
+		return err
+	}
+	aff.RequiredDuringSchedulingIgnoredDuringExecution = ns
+	aff.PreferredDuringSchedulingIgnoredDuringExecution = nil
+	return nil
+}
+
+// schedulable returns true when pods can be scheduled on the node.
+func schedulable(node corev1.Node) bool {
+	if node.Spec.Unschedulable {
+		return false
+	}
+	for _, c := range node.Status.Conditions {
+		if c.Type == corev1.NodeReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// eligibleNodes returns the names of the ready and schedulable nodes of the selection.
+func (n nodeSelection) eligibleNodes(client *kubernetes.Clientset) ([]string, error) {
+	if n.node != "" {
+		node, err := client.CoreV1().Nodes().Get(context.TODO(), n.node, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("unable to get node %s: %v", n.node, err)
+		}
+		if !schedulable(*node) {
+			return nil, fmt.Errorf("node %s is not ready or not schedulable", n.node)
+		}
+		return []string{n.node}, nil
+	}
+	if _, err := labels.Parse(n.selector); err != nil {
+		return nil, fmt.Errorf("invalid node selector %q: %v", n.selector, err)
+	}
+	nodes, err := client.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{LabelSelector: n.selector})
+	if err != nil {
+		return nil, fmt.Errorf("unable to query nodes: %v", err)
+	}
+	var names []string
+	for _, node := range nodes.Items {
+		if schedulable(node) {
+			names = append(names, node.Name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no ready and schedulable node matches the node selector %q", n.selector)
+	}
+	return names, nil
+}
+
+// checkSelections validates the combination of the client and server selections with the scenario.
+func checkSelections(s *config.PerfScenarios, cs, ss nodeSelection) error {
+	if cs.node != "" && cs.selector != "" {
+		return fmt.Errorf("the client node and client node selector are mutually exclusive")
+	}
+	if ss.node != "" && ss.selector != "" {
+		return fmt.Errorf("the server node and server node selector are mutually exclusive")
+	}
+	if ss.isSet() && s.ExternalServer {
+		return fmt.Errorf("the server node can not be selected with an external server")
+	}
+	if ss.isSet() && s.NodeLocal {
+		return fmt.Errorf("the server runs on the client node with --local, select the client node instead")
+	}
+	return nil
+}
+
+// ValidatePlacement checks the client and server node selections before anything is
+// deployed: the nodes must exist, be ready and schedulable, and the client and
+// server must be able to run on different nodes unless running with --local.
+func ValidatePlacement(client *kubernetes.Clientset, s *config.PerfScenarios) error {
+	cs, ss := clientSelection(s), serverSelection(s)
+	if err := checkSelections(s, cs, ss); err != nil {
+		return err
+	}
+	var clientNodes, serverNodes []string
+	var err error
+	if cs.isSet() {
+		if clientNodes, err = cs.eligibleNodes(client); err != nil {
+			return err
+		}
+		log.Infof("📌 Client pinned to %s, eligible nodes: %v", cs, clientNodes)
+	}
+	if ss.isSet() {
+		if serverNodes, err = ss.eligibleNodes(client); err != nil {
+			return err
+		}
+		log.Infof("📌 Server pinned to %s, eligible nodes: %v", ss, serverNodes)
+	}
+	if len(clientNodes) == 1 && len(serverNodes) == 1 && clientNodes[0] == serverNodes[0] {
+		return fmt.Errorf("the client and server are both pinned to node %s, use --local to run them on the same node", clientNodes[0])
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package k8s
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestNodeSelectionPin(t *testing.T) {
+	preferred := []corev1.PreferredSchedulingTerm{{Weight: 100}}
+	tests := []struct {
+		name string
+		sel  nodeSelection
+		want *corev1.NodeSelector
+	}{
+		{
+			name: "node",
+			sel:  nodeSelection{node: "worker-1"},
+			want: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+				MatchFields: []corev1.NodeSelectorRequirement{{Key: "metadata.name", Operator: corev1.NodeSelectorOpIn, Values: []string{"worker-1"}}},
+			}}},
+		},
+		{
+			name: "selector",
+			sel:  nodeSelection{selector: "nic=cx6,pool in (a,b),!spot"},
+			want: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+				MatchExpressions: []corev1.NodeSelectorRequirement{
+					{Key: "nic", Operator: corev1.NodeSelectorOpIn, Values: []string{"cx6"}},
+					{Key: "pool", Operator: corev1.NodeSelectorOpIn, Values: []string{"a", "b"}},
+					{Key: "spot", Operator: corev1.NodeSelectorOpDoesNotExist, Values: []string{}},
+				},
+			}}},
+		},
+	}
+	for _, tt := range tests {
+		aff := corev1.NodeAffinity{PreferredDuringSchedulingIgnoredDuringExecution: preferred}
+		if err := tt.sel.pin(&aff); err != nil {
+			t.Fatalf("%s: pin: %v", tt.name, err)
+		}
+		if !reflect.DeepEqual(aff.RequiredDuringSchedulingIgnoredDuringExecution, tt.want) {
+			t.Fatalf("%s: got %+v, want %+v", tt.name, aff.RequiredDuringSchedulingIgnoredDuringExecution, tt.want)
+		}
+		if aff.PreferredDuringSchedulingIgnoredDuringExecution != nil {
+			t.Fatalf("%s: the preferences must be dropped for a pinned node", tt.name)
+		}
+	}
+	aff := corev1.NodeAffinity{PreferredDuringSchedulingIgnoredDuringExecution: preferred}
+	if err := (nodeSelection{}).pin(&aff); err != nil || aff.PreferredDuringSchedulingIgnoredDuringExecution == nil {
+		t.Fatalf("an empty selection must leave the affinity alone")
+	}
+	if err := (nodeSelection{selector: "a=(b"}).pin(&aff); err == nil {
+		t.Fatalf("expected an error for an invalid selector")
+	}
+}
+
+func TestCheckSelections(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       config.PerfScenarios
+		wantErr bool
+	}{
+		{"client and server nodes", config.PerfScenarios{ClientNode: "a", ServerNode: "b"}, false},
+		{"client node and selector", config.PerfScenarios{ClientNode: "a", ClientNodeSelector: "x=y"}, true},
+		{"server with external server", config.PerfScenarios{ServerNode: "b", ExternalServer: true}, true},
+		{"server with local", config.PerfScenarios{ServerNode: "b", NodeLocal: true}, true},
+		{"client with local", config.PerfScenarios{ClientNode: "a", NodeLocal: true}, false},
+	}
+	for _, tt := range tests {
+		err := checkSelections(&tt.s, clientSelection(&tt.s), serverSelection(&tt.s))
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("%s: got error %v, want error %t", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestSchedulable(t *testing.T) {
+	ready := corev1.NodeStatus{Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}}}
+	notReady := corev1.NodeStatus{Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionFalse}}}
+	if !schedulable(corev1.Node{Status: ready}) {
+		t.Fatalf("a ready node must be schedulable")
+	}
+	if schedulable(corev1.Node{Status: notReady}) {
+		t.Fatalf("a not ready node must not be schedulable")
+	}
+	if schedulable(corev1.Node{Spec: corev1.NodeSpec{Unschedulable: true}, Status: ready}) {
+		t.Fatalf("a cordoned node must not be schedulable")
+	}
+}
@@ -0,0 +1,147 @@
+package k8s
+
+import (
+	"fmt"
+
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// hugepagesMount is where the hugepages are mounted in the containers.
+const hugepagesMount = "/dev/hugepages"
+
+// Resources of the containers of the k8s-netperf pods and their placement, see SetResources.
+var (
+	podResources     corev1.ResourceRequirements
+	hugepageSize     string
+	runtimeClassName string
+	schedulerName    string
+)
+
+// SetResources sets the resources of the containers of the k8s-netperf pods, the runtime
+// class and the scheduler of the pods. With Guaranteed, the limits are the requests, and the
+// CPU request must be an integer number of CPUs.
+func SetResources(r config.Resources) error {
+	var res corev1.ResourceRequirements
+	for _, q := range []struct {
+		list  *corev1.ResourceList
+		name  corev1.ResourceName
+		value string
+	}{
+		{&res.Requests, corev1.ResourceCPU, r.CPU},
+		{&res.Requests, corev1.ResourceMemory, r.Memory},
+		{&res.Limits, corev1.ResourceCPU, r.CPULimit},
+		{&res.Limits, corev1.ResourceMemory, r.MemoryLimit},
+	} {
+		if q.value == "" {
+			continue
+		}
+		v, err := resource.ParseQuantity(q.value)
+		if err != nil || v.Sign() <= 0 {
+			return fmt.Errorf("invalid %s %q, expected a positive quantity", q.name, q.value)
+		}
+		if *q.list == nil {
+			*q.list = corev1.ResourceList{}
+		}
+		(*q.list)[q.name] = v
+	}
+	for name, limit := range res.Limits {
+		if request, ok := res.Requests[name]; ok && limit.Cmp(request) < 0 {
+			return fmt.Errorf("the %s limit %s is lower than the request %s", name, limit.String(), request.String())
+		}
+	}
+	if r.Guaranteed {
+		cpu, okCPU := res.Requests[corev1.ResourceCPU]
+		_, okMemory := res.Requests[corev1.ResourceMemory]
+		if !okCPU || !okMemory {
+			return fmt.Errorf("guaranteed requires the cpu and memory requests")
+		}
+		if cpu.MilliValue()%1000 != 0 {
+			return fmt.Errorf("guaranteed requires an integer number of CPUs, got %s", cpu.String())
+		}
+		for name, limit := range res.Limits {
+			if limit.Cmp(res.Requests[name]) != 0 {
+				return fmt.Errorf("guaranteed requires the %s limit to be the request", name)
+			}
+		}
+		res.Limits = res.Requests.DeepCopy()
+	}
+	size := ""
+	if r.Hugepages != "" {
+		size = r.HugepageSize
+		if size == "" {
+			size = "2Mi"
+		}
+		if size != "2Mi" && size != "1Gi" {
+			return fmt.Errorf("invalid hugepage size %q, expected 2Mi or 1Gi", size)
+		}
+		v, err := resource.ParseQuantity(r.Hugepages)
+		if err != nil || v.Sign() <= 0 {
+			return fmt.Errorf("invalid hugepages %q, expected a positive quantity", r.Hugepages)
+		}
+		if len(res.Requests) == 0 {
+			return fmt.Errorf("hugepages require the cpu or memory request")
+		}
+		// Hugepages are not overcommitted, their limit is their request.
+		name := corev1.ResourceName(corev1.ResourceHugePagesPrefix + size)
+		res.Requests[name] = v
+		if res.Limits == nil {
+			res.Limits = corev1.ResourceList{}
+		}
+		res.Limits[name] = v
+	} else if r.HugepageSize != "" {
+		return fmt.Errorf("hugepageSize requires hugepages")
+	}
+	if r.RuntimeClassName != "" && len(validation.IsDNS1123Subdomain(r.RuntimeClassName)) > 0 {
+		return fmt.Errorf("invalid runtime class name %q", r.RuntimeClassName)
+	}
+	if r.SchedulerName != "" && len(validation.IsDNS1123Subdomain(r.SchedulerName)) > 0 {
+		return fmt.Errorf("invalid scheduler name %q", r.SchedulerName)
+	}
+	podResources, hugepageSize, runtimeClassName, schedulerName = res, size, r.RuntimeClassName, r.SchedulerName
+	return nil
+}
+
+// containerResources returns the resources of the containers of dp, those set by
+// SetResources and the extended resources of dp, e.g. SR-IOV VFs, whose limits are their
+// requests.
+func containerResources(dp DeploymentParams) corev1.ResourceRequirements {
+	res := corev1.ResourceRequirements{
+		Requests: podResources.Requests.DeepCopy(),
+		Limits:   podResources.Limits.DeepCopy(),
+	}
+	for name, v := range dp.ResourceRequests {
+		if res.Requests == nil {
+			res.Requests = corev1.ResourceList{}
+		}
+		if res.Limits == nil {
+			res.Limits = corev1.ResourceList{}
+		}
+		res.Requests[name] = v
+		res.Limits[name] = v
+	}
+	return res
+}
+
+// hugepagesVolume returns the volume of the hugepages of the pods, nil without hugepages.
+func hugepagesVolume() *corev1.Volume {
+	if hugepageSize == "" {
+		return nil
+	}
+	return &corev1.Volume{
+		Name: "hugepages",
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{Medium: corev1.StorageMedium("HugePages-" + hugepageSize)},
+		},
+	}
+}
+
+// QOSClass returns the QoS class of the pods, empty when unknown.
+func QOSClass(pods corev1.PodList) string {
+	if len(pods.Items) == 0 {
+		return ""
+	}
+	return string(pods.Items[0].Status.QOSClass)
+}
@@ -0,0 +1,106 @@
+package k8s
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestSetResources(t *testing.T) {
+	tests := []struct {
+		name     string
+		res      config.Resources
+		requests string
+		limits   string
+		wantErr  bool
+	}{
+		{"none", config.Resources{}, "", "", false},
+		{"burstable", config.Resources{CPU: "500m", Memory: "512Mi", CPULimit: "2"}, "cpu=500m memory=512Mi", "cpu=2", false},
+		{"guaranteed", config.Resources{CPU: "2", Memory: "1Gi", Guaranteed: true}, "cpu=2 memory=1Gi", "cpu=2 memory=1Gi", false},
+		{"guaranteed with limits", config.Resources{CPU: "2", Memory: "1Gi", CPULimit: "2", Guaranteed: true}, "cpu=2 memory=1Gi", "cpu=2 memory=1Gi", false},
+		{"hugepages", config.Resources{Memory: "1Gi", Hugepages: "512Mi"}, "hugepages-2Mi=512Mi memory=1Gi", "hugepages-2Mi=512Mi", false},
+		{"guaranteed fractional cpu", config.Resources{CPU: "1500m", Memory: "1Gi", Guaranteed: true}, "", "", true},
+		{"guaranteed without memory", config.Resources{CPU: "2", Guaranteed: true}, "", "", true},
+		{"guaranteed other limit", config.Resources{CPU: "2", Memory: "1Gi", MemoryLimit: "2Gi", Guaranteed: true}, "", "", true},
+		{"limit below request", config.Resources{CPU: "2", CPULimit: "1"}, "", "", true},
+		{"bad quantity", config.Resources{Memory: "lots"}, "", "", true},
+		{"hugepages without request", config.Resources{Hugepages: "1Gi"}, "", "", true},
+		{"bad hugepage size", config.Resources{Memory: "1Gi", Hugepages: "1Gi", HugepageSize: "4Ki"}, "", "", true},
+		{"bad runtime class", config.Resources{RuntimeClassName: "Kata_"}, "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() { _ = SetResources(config.Resources{}) }()
+			err := SetResources(tt.res)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SetResources(%+v) error = %v, wantErr %t", tt.res, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got := resourceString(podResources.Requests); got != tt.requests {
+				t.Fatalf("SetResources(%+v) requests = %q, want %q", tt.res, got, tt.requests)
+			}
+			if got := resourceString(podResources.Limits); got != tt.limits {
+				t.Fatalf("SetResources(%+v) limits = %q, want %q", tt.res, got, tt.limits)
+			}
+		})
+	}
+}
+
+func TestPodTemplateResources(t *testing.T) {
+	defer func() { _ = SetResources(config.Resources{}) }()
+	err := SetResources(config.Resources{CPU: "2", Memory: "1Gi", Guaranteed: true, Hugepages: "1Gi", HugepageSize: "1Gi", RuntimeClassName: "kata", SchedulerName: "topo-aware-scheduler"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	vf := corev1.ResourceName("openshift.io/sriovnic")
+	dp := DeploymentParams{Name: "server", Commands: [][]string{{"sleep"}, {"sleep"}}, ResourceRequests: corev1.ResourceList{vf: resource.MustParse("1")}}
+	tpl := podTemplate(dp)
+	for _, c := range tpl.Spec.Containers {
+		if got := resourceString(c.Resources.Limits); got != "cpu=2 hugepages-1Gi=1Gi memory=1Gi openshift.io/sriovnic=1" {
+			t.Fatalf("container %s limits = %q", c.Name, got)
+		}
+		if len(c.VolumeMounts) != 1 || c.VolumeMounts[0].MountPath != hugepagesMount {
+			t.Fatalf("container %s mounts = %+v", c.Name, c.VolumeMounts)
+		}
+	}
+	if len(tpl.Spec.Volumes) != 1 || tpl.Spec.Volumes[0].EmptyDir.Medium != "HugePages-1Gi" {
+		t.Fatalf("volumes = %+v", tpl.Spec.Volumes)
+	}
+	if tpl.Spec.RuntimeClassName == nil || *tpl.Spec.RuntimeClassName != "kata" || tpl.Spec.SchedulerName != "topo-aware-scheduler" {
+		t.Fatalf("runtime class %v scheduler %q", tpl.Spec.RuntimeClassName, tpl.Spec.SchedulerName)
+	}
+	// The global resources are not modified by the extended resources of a deployment.
+	if _, ok := podResources.Limits[vf]; ok {
+		t.Fatalf("the SR-IOV VF leaked in the resources of all pods: %v", podResources.Limits)
+	}
+	dp.HostNetwork = true
+	if tpl := podTemplate(dp); tpl.Spec.RuntimeClassName != nil {
+		t.Fatalf("host network pod runs with runtime class %s", *tpl.Spec.RuntimeClassName)
+	}
+}
+
+func TestQOSClass(t *testing.T) {
+	if got := QOSClass(corev1.PodList{}); got != "" {
+		t.Fatalf("QOSClass() without pods = %q", got)
+	}
+	pods := corev1.PodList{Items: []corev1.Pod{{Status: corev1.PodStatus{QOSClass: corev1.PodQOSGuaranteed}}}}
+	if got := QOSClass(pods); got != "Guaranteed" {
+		t.Fatalf("QOSClass() = %q, want Guaranteed", got)
+	}
+}
+
+// resourceString returns the resources as sorted name=quantity pairs.
+func resourceString(l corev1.ResourceList) string {
+	var s []string
+	for name, q := range l {
+		s = append(s, string(name)+"="+q.String())
+	}
+	sort.Strings(s)
+	return strings.Join(s, " ")
+}
@@ -0,0 +1,63 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/config"
+	log "github.com/cloud-bulldozer/k8s-netperf/pkg/logging"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Roles of the client and server pods of the sandboxed runtime class.
+const clientSandboxRole = "client-sandbox"
+const serverSandboxRole = "server-sandbox"
+
+// buildSandbox deploys the client and server pods of the sandboxed runtime class, on the nodes
+// of the client and server pods of the default runtime, so that only the runtime differs.
+// cdp and sdp are the deployments of the client and server pods of the default runtime.
+func buildSandbox(client *kubernetes.Clientset, s *config.PerfScenarios, cdp, sdp DeploymentParams) error {
+	if s.SandboxRuntimeClass == runtimeClassName {
+		return fmt.Errorf("the sandboxed runtime class %s is the runtime class of all the pods", s.SandboxRuntimeClass)
+	}
+	if _, err := client.NodeV1().RuntimeClasses().Get(context.TODO(), s.SandboxRuntimeClass, metav1.GetOptions{}); err != nil {
+		return fmt.Errorf("unable to get the runtime class %s: %v", s.SandboxRuntimeClass, err)
+	}
+	log.Infof("📦 Deploying the client and server with the runtime class %s", s.SandboxRuntimeClass)
+	cdp.Name = clientSandboxRole
+	cdp.Labels = map[string]string{"role": clientSandboxRole}
+	cdp.RuntimeClassName = s.SandboxRuntimeClass
+	cdp.PodAntiAffinity = corev1.PodAntiAffinity{}
+	cdp.PodAffinity = roleAffinity(clientAcrossRole)
+	sdp.Name = serverSandboxRole
+	sdp.Labels = map[string]string{"role": serverSandboxRole}
+	sdp.RuntimeClassName = s.SandboxRuntimeClass
+	sdp.PodAntiAffinity = corev1.PodAntiAffinity{}
+	sdp.PodAffinity = roleAffinity(serverRole)
+	var err error
+	if s.SandboxClient, err = deployDeployment(client, cdp); err != nil {
+		return err
+	}
+	if s.SandboxServer, err = deployDeployment(client, sdp); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Sandboxed returns the scenarios with the client and server pods of the sandboxed runtime
+// class in place of those of the default runtime.
+func Sandboxed(s config.PerfScenarios) config.PerfScenarios {
+	s.ClientAcross = s.SandboxClient
+	s.Server = s.SandboxServer
+	return s
+}
+
+// RuntimeClass returns the runtime class of the pods, empty for the default runtime.
+func RuntimeClass(pods corev1.PodList) string {
+	if len(pods.Items) == 0 || pods.Items[0].Spec.RuntimeClassName == nil {
+		return ""
+	}
+	return *pods.Items[0].Spec.RuntimeClassName
+}
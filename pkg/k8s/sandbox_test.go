@@ -0,0 +1,30 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestSandboxRuntimeClass(t *testing.T) {
+	defer func() { _ = SetResources(config.Resources{}) }()
+	if err := SetResources(config.Resources{RuntimeClassName: "performance"}); err != nil {
+		t.Fatal(err)
+	}
+	tpl := podTemplate(DeploymentParams{Name: clientSandboxRole, Commands: [][]string{{"sleep"}}, RuntimeClassName: "kata"})
+	if tpl.Spec.RuntimeClassName == nil || *tpl.Spec.RuntimeClassName != "kata" {
+		t.Fatalf("the sandboxed pods run with runtime class %v, want kata", tpl.Spec.RuntimeClassName)
+	}
+	pods := corev1.PodList{Items: []corev1.Pod{{Spec: tpl.Spec}}}
+	if got := RuntimeClass(pods); got != "kata" {
+		t.Fatalf("RuntimeClass() = %q, want kata", got)
+	}
+	if got := RuntimeClass(corev1.PodList{Items: []corev1.Pod{{}}}); got != "" {
+		t.Fatalf("RuntimeClass() of the default runtime = %q, want none", got)
+	}
+	s := config.PerfScenarios{ClientAcross: corev1.PodList{}, SandboxClient: pods, SandboxServer: pods}
+	if sb := Sandboxed(s); RuntimeClass(sb.ClientAcross) != "kata" || RuntimeClass(sb.Server) != "kata" {
+		t.Fatalf("Sandboxed() does not use the sandboxed client and server pods")
+	}
+}
@@ -0,0 +1,129 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Scheduling of the k8s-netperf pods and VMs, see SetScheduling.
+var (
+	tolerations       []corev1.Toleration
+	extraTerms        []corev1.NodeSelectorTerm
+	extraSelectors    []string
+	priorityClassName string
+)
+
+// SetScheduling sets the tolerations, the extra node selector terms and the priority class of
+// the k8s-netperf pods and VMs. Each term is a label selector, the nodes matching one of them
+// are eligible besides the worker nodes.
+func SetScheduling(sc config.Scheduling) error {
+	var tols []corev1.Toleration
+	for _, t := range sc.Tolerations {
+		tol, err := toleration(t)
+		if err != nil {
+			return err
+		}
+		tols = append(tols, tol)
+	}
+	var terms []corev1.NodeSelectorTerm
+	for _, sel := range sc.NodeSelectorTerms {
+		ns, err := nodeSelection{selector: sel}.nodeSelector()
+		if err != nil {
+			return err
+		}
+		terms = append(terms, ns.NodeSelectorTerms...)
+	}
+	if sc.PriorityClassName != "" && len(validation.IsDNS1123Subdomain(sc.PriorityClassName)) > 0 {
+		return fmt.Errorf("invalid priority class name %q", sc.PriorityClassName)
+	}
+	tolerations, extraTerms, extraSelectors, priorityClassName = tols, terms, sc.NodeSelectorTerms, sc.PriorityClassName
+	return nil
+}
+
+// toleration validates the toleration of the configuration. The operator is Exists without
+// a value, Equal otherwise, unless set.
+func toleration(t config.Toleration) (corev1.Toleration, error) {
+	tol := corev1.Toleration{
+		Key:      t.Key,
+		Operator: corev1.TolerationOperator(t.Operator),
+		Value:    t.Value,
+		Effect:   corev1.TaintEffect(t.Effect),
+	}
+	if tol.Operator == "" {
+		tol.Operator = corev1.TolerationOpEqual
+		if tol.Value == "" {
+			tol.Operator = corev1.TolerationOpExists
+		}
+	}
+	switch tol.Operator {
+	case corev1.TolerationOpExists:
+		if tol.Value != "" {
+			return tol, fmt.Errorf("invalid toleration %s, the Exists operator takes no value", t.Key)
+		}
+	case corev1.TolerationOpEqual:
+		if tol.Key == "" {
+			return tol, fmt.Errorf("invalid toleration, the Equal operator requires a key")
+		}
+	default:
+		return tol, fmt.Errorf("invalid toleration operator %q, expected Exists or Equal", t.Operator)
+	}
+	switch tol.Effect {
+	case "", corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute:
+	default:
+		return tol, fmt.Errorf("invalid toleration effect %q, expected NoSchedule, PreferNoSchedule or NoExecute", t.Effect)
+	}
+	return tol, nil
+}
+
+// ParseToleration parses a toleration in the format of the taints of kubectl, key[=value][:effect],
+// e.g. dedicated=netperf:NoSchedule. Without a value, the toleration tolerates every value of
+// the key.
+func ParseToleration(s string) (config.Toleration, error) {
+	var t config.Toleration
+	rest, effect, _ := strings.Cut(s, ":")
+	t.Effect = effect
+	t.Key, t.Value, _ = strings.Cut(rest, "=")
+	if _, err := toleration(t); err != nil {
+		return t, err
+	}
+	return t, nil
+}
+
+// withExtraTerms returns the node selector with the extra node selector terms.
+func withExtraTerms(ns *corev1.NodeSelector) *corev1.NodeSelector {
+	if len(extraTerms) == 0 {
+		return ns
+	}
+	terms := append([]corev1.NodeSelectorTerm{}, ns.NodeSelectorTerms...)
+	return &corev1.NodeSelector{NodeSelectorTerms: append(terms, extraTerms...)}
+}
+
+// extraNodes returns the number of nodes matching the extra node selector terms, other than
+// the known nodes.
+func extraNodes(client *kubernetes.Clientset, known []corev1.Node) (int, error) {
+	seen := map[string]bool{}
+	for _, n := range known {
+		seen[n.Name] = true
+	}
+	count := 0
+	for _, sel := range extraSelectors {
+		nodes, err := client.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{LabelSelector: sel})
+		if err != nil {
+			return 0, fmt.Errorf("unable to query nodes: %v", err)
+		}
+		for _, n := range nodes.Items {
+			if !seen[n.Name] {
+				seen[n.Name] = true
+				count++
+			}
+		}
+	}
+	return count, nil
+}
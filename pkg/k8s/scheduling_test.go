@@ -0,0 +1,73 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestParseToleration(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    corev1.Toleration
+		wantErr bool
+	}{
+		{"dedicated=netperf:NoSchedule", corev1.Toleration{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "netperf", Effect: corev1.TaintEffectNoSchedule}, false},
+		{"node-role.kubernetes.io/gpu:NoExecute", corev1.Toleration{Key: "node-role.kubernetes.io/gpu", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute}, false},
+		{"dedicated", corev1.Toleration{Key: "dedicated", Operator: corev1.TolerationOpExists}, false},
+		{"dedicated=netperf:Never", corev1.Toleration{}, true},
+		{"=netperf", corev1.Toleration{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			ct, err := ParseToleration(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseToleration(%q) error = %v, wantErr %t", tt.in, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			got, err := toleration(ct)
+			if err != nil || got != tt.want {
+				t.Fatalf("ParseToleration(%q) = %+v, %v, want %+v", tt.in, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetScheduling(t *testing.T) {
+	defer func() { _ = SetScheduling(config.Scheduling{}) }()
+	for _, sc := range []config.Scheduling{
+		{Tolerations: []config.Toleration{{Key: "dedicated", Operator: "Exists", Value: "netperf"}}},
+		{Tolerations: []config.Toleration{{Key: "dedicated", Operator: "Lt"}}},
+		{NodeSelectorTerms: []string{"pool in (gpu"}},
+		{PriorityClassName: "High_Priority"},
+	} {
+		if err := SetScheduling(sc); err == nil {
+			t.Fatalf("SetScheduling(%+v) did not fail", sc)
+		}
+	}
+	err := SetScheduling(config.Scheduling{
+		Tolerations:       []config.Toleration{{Key: "dedicated", Value: "netperf", Effect: "NoSchedule"}},
+		NodeSelectorTerms: []string{"node-role.kubernetes.io/gpu=", "pool=telco,!maintenance"},
+		PriorityClassName: "netperf",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ns := workerNodeSelector()
+	if len(ns.NodeSelectorTerms) != 3 || len(ns.NodeSelectorTerms[2].MatchExpressions) != 2 {
+		t.Fatalf("workerNodeSelector() = %+v, want the worker term and the 2 extra terms", ns.NodeSelectorTerms)
+	}
+	tpl := podTemplate(DeploymentParams{Name: "client", Commands: [][]string{{"sleep"}}})
+	if len(tpl.Spec.Tolerations) != 1 || tpl.Spec.Tolerations[0].Operator != corev1.TolerationOpEqual || tpl.Spec.PriorityClassName != "netperf" {
+		t.Fatalf("the pod template does not use the scheduling settings: %+v", tpl.Spec)
+	}
+	if err := SetScheduling(config.Scheduling{}); err != nil {
+		t.Fatal(err)
+	}
+	if ns := workerNodeSelector(); len(ns.NodeSelectorTerms) != 1 {
+		t.Fatalf("workerNodeSelector() without extra terms = %+v", ns.NodeSelectorTerms)
+	}
+}
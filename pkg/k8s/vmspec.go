@@ -0,0 +1,120 @@
+package k8s
+
+import (
+	"fmt"
+
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	v1 "kubevirt.io/api/core/v1"
+)
+
+// DefaultVMMemory is the memory of the VMs, unless set.
+const DefaultVMMemory = "4096Mi"
+
+// CheckVMSpec returns why the VM spec is invalid, nil when it is valid.
+func CheckVMSpec(spec config.VMSpec) error {
+	if spec.Sockets == 0 || spec.Cores == 0 || spec.Threads == 0 {
+		return fmt.Errorf("the VM needs at least one socket, core and thread, got %d sockets, %d cores and %d threads", spec.Sockets, spec.Cores, spec.Threads)
+	}
+	mem, err := resource.ParseQuantity(vmMemory(spec))
+	if err != nil {
+		return fmt.Errorf("invalid VM memory %q: %v", spec.Memory, err)
+	}
+	switch spec.Hugepages {
+	case "":
+	case "2Mi", "1Gi":
+		page := resource.MustParse(spec.Hugepages)
+		if mem.Value()%page.Value() != 0 {
+			return fmt.Errorf("the VM memory %s is not a multiple of the %s hugepages", mem.String(), spec.Hugepages)
+		}
+	default:
+		return fmt.Errorf("invalid VM hugepages %q, expected 2Mi or 1Gi", spec.Hugepages)
+	}
+	if spec.NUMA && (!spec.DedicatedCPUs || spec.Hugepages == "") {
+		return fmt.Errorf("the VM NUMA topology requires dedicated CPUs and hugepages")
+	}
+	return nil
+}
+
+// vmMemory returns the memory of the VM, DefaultVMMemory when not set.
+func vmMemory(spec config.VMSpec) string {
+	if spec.Memory == "" {
+		return DefaultVMMemory
+	}
+	return spec.Memory
+}
+
+// vmResources returns the resources of the VM. With dedicated CPUs, KubeVirt derives the CPU
+// requests from the topology, and the memory limit keeps the virt-launcher pod Guaranteed.
+func vmResources(spec config.VMSpec, sriovNetwork string) v1.ResourceRequirements {
+	res := v1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse(vmMemory(spec)),
+		},
+	}
+	if spec.DedicatedCPUs {
+		res.Limits = corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse(vmMemory(spec)),
+		}
+	} else {
+		res.Requests[corev1.ResourceCPU] = resource.MustParse("500m")
+	}
+	if sriovNetwork != "" {
+		res.Requests[corev1.ResourceName("openshift.io/"+sriovNetwork)] = resource.MustParse("1")
+	}
+	return res
+}
+
+// vmDomain returns the CPU, memory and machine of the domain of the VM.
+func vmDomain(spec config.VMSpec) (*v1.CPU, *v1.Memory, *v1.Machine) {
+	cpu := &v1.CPU{
+		Sockets:               spec.Sockets,
+		Cores:                 spec.Cores,
+		Threads:               spec.Threads,
+		DedicatedCPUPlacement: spec.DedicatedCPUs,
+	}
+	if spec.NUMA {
+		cpu.NUMA = &v1.NUMA{GuestMappingPassthrough: &v1.NUMAGuestMappingPassthrough{}}
+	}
+	var mem *v1.Memory
+	if spec.Hugepages != "" {
+		mem = &v1.Memory{Hugepages: &v1.Hugepages{PageSize: spec.Hugepages}}
+	}
+	var machine *v1.Machine
+	if spec.MachineType != "" {
+		machine = &v1.Machine{Type: spec.MachineType}
+	}
+	return cpu, mem, machine
+}
+
+// EffectiveVMSpec returns the spec of the VMI as KubeVirt runs it, with the defaults
+// KubeVirt applied, e.g. the machine type.
+func EffectiveVMSpec(vmi *v1.VirtualMachineInstance) config.VMSpec {
+	d := vmi.Spec.Domain
+	var spec config.VMSpec
+	if d.CPU != nil {
+		spec.Sockets, spec.Cores, spec.Threads = d.CPU.Sockets, d.CPU.Cores, d.CPU.Threads
+		spec.DedicatedCPUs = d.CPU.DedicatedCPUPlacement
+		spec.NUMA = d.CPU.NUMA != nil && d.CPU.NUMA.GuestMappingPassthrough != nil
+	}
+	if m, ok := d.Resources.Requests[corev1.ResourceMemory]; ok {
+		spec.Memory = m.String()
+	}
+	if d.Memory != nil {
+		if d.Memory.Guest != nil {
+			spec.Memory = d.Memory.Guest.String()
+		}
+		if d.Memory.Hugepages != nil {
+			spec.Hugepages = d.Memory.Hugepages.PageSize
+		}
+	}
+	spec.MultiQueue = d.Devices.NetworkInterfaceMultiQueue != nil && *d.Devices.NetworkInterfaceMultiQueue
+	if d.Machine != nil {
+		spec.MachineType = d.Machine.Type
+	}
+	if vmi.Status.QOSClass != nil {
+		spec.QOSClass = string(*vmi.Status.QOSClass)
+	}
+	return spec
+}
@@ -0,0 +1,80 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	v1 "kubevirt.io/api/core/v1"
+)
+
+func TestCheckVMSpec(t *testing.T) {
+	base := config.VMSpec{Sockets: 2, Cores: 2, Threads: 1}
+	tests := []struct {
+		name    string
+		edit    func(*config.VMSpec)
+		wantErr bool
+	}{
+		{"default", func(s *config.VMSpec) {}, false},
+		{"no cores", func(s *config.VMSpec) { s.Cores = 0 }, true},
+		{"bad memory", func(s *config.VMSpec) { s.Memory = "lots" }, true},
+		{"hugepages", func(s *config.VMSpec) { s.Memory = "8Gi"; s.Hugepages = "1Gi" }, false},
+		{"memory not a multiple of the pages", func(s *config.VMSpec) { s.Memory = "1500Mi"; s.Hugepages = "1Gi" }, true},
+		{"bad hugepages", func(s *config.VMSpec) { s.Hugepages = "4Ki" }, true},
+		{"numa", func(s *config.VMSpec) { s.NUMA, s.DedicatedCPUs, s.Hugepages = true, true, "2Mi" }, false},
+		{"numa without hugepages", func(s *config.VMSpec) { s.NUMA, s.DedicatedCPUs = true, true }, true},
+		{"numa without dedicated CPUs", func(s *config.VMSpec) { s.NUMA, s.Hugepages = true, "2Mi" }, true},
+	}
+	for _, tt := range tests {
+		spec := base
+		tt.edit(&spec)
+		if err := CheckVMSpec(spec); (err != nil) != tt.wantErr {
+			t.Fatalf("%s: CheckVMSpec(%+v) error = %v, wantErr %t", tt.name, spec, err, tt.wantErr)
+		}
+	}
+}
+
+func TestVMResources(t *testing.T) {
+	shared := vmResources(config.VMSpec{}, "")
+	if mem := shared.Requests[corev1.ResourceMemory]; mem.Cmp(resource.MustParse(DefaultVMMemory)) != 0 {
+		t.Fatalf("memory request = %s, want %s", mem.String(), DefaultVMMemory)
+	}
+	if _, ok := shared.Requests[corev1.ResourceCPU]; !ok || shared.Limits != nil {
+		t.Fatalf("shared CPUs request CPU without limits, got %+v", shared)
+	}
+	dedicated := vmResources(config.VMSpec{Memory: "8Gi", DedicatedCPUs: true}, "ens1f0")
+	if _, ok := dedicated.Requests[corev1.ResourceCPU]; ok {
+		t.Fatalf("dedicated CPUs must not request CPU, got %+v", dedicated.Requests)
+	}
+	if mem := dedicated.Limits[corev1.ResourceMemory]; mem.String() != "8Gi" {
+		t.Fatalf("dedicated CPUs memory limit = %s, want 8Gi", mem.String())
+	}
+	if _, ok := dedicated.Requests["openshift.io/ens1f0"]; !ok {
+		t.Fatalf("the SR-IOV VF is not requested, got %+v", dedicated.Requests)
+	}
+}
+
+func TestEffectiveVMSpec(t *testing.T) {
+	spec := config.VMSpec{Sockets: 1, Cores: 4, Threads: 2, Memory: "8Gi", DedicatedCPUs: true, NUMA: true, Hugepages: "1Gi", MultiQueue: true, MachineType: "q35"}
+	cpu, mem, machine := vmDomain(spec)
+	qos := corev1.PodQOSGuaranteed
+	vmi := &v1.VirtualMachineInstance{
+		Spec: v1.VirtualMachineInstanceSpec{Domain: v1.DomainSpec{
+			Resources: vmResources(spec, ""),
+			CPU:       cpu,
+			Memory:    mem,
+			Machine:   machine,
+			Devices:   v1.Devices{NetworkInterfaceMultiQueue: &spec.MultiQueue},
+		}},
+		Status: v1.VirtualMachineInstanceStatus{QOSClass: &qos},
+	}
+	want := spec
+	want.QOSClass = "Guaranteed"
+	if got := EffectiveVMSpec(vmi); got != want {
+		t.Fatalf("EffectiveVMSpec() = %+v, want %+v", got, want)
+	}
+	if got := EffectiveVMSpec(&v1.VirtualMachineInstance{}); got != (config.VMSpec{}) {
+		t.Fatalf("EffectiveVMSpec() of an empty VMI = %+v, want none", got)
+	}
+}
@@ -0,0 +1,207 @@
+package k8s
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/config"
+	log "github.com/cloud-bulldozer/k8s-netperf/pkg/logging"
+	gws "github.com/gorilla/websocket"
+	"github.com/melbahja/goph"
+	"golang.org/x/crypto/ssh"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/transport/websocket"
+)
+
+// How k8s-netperf reaches the VMs. With route, the ssh port of the client VM is exposed with a
+// NodePort service and a route, and the key of the user is used. The other ones tunnel ssh
+// through the KubeVirt API, with a key generated for the run.
+const (
+	VMAccessRoute       = "route"
+	VMAccessPortForward = "port-forward"
+	VMAccessVSOCK       = "vsock"
+)
+
+const (
+	// sshKeyFile is the private key of the run, in its run directory.
+	sshKeyFile = "id_ed25519"
+	// vsockPort is the VSOCK port the VMs forward to their ssh port.
+	vsockPort = 22
+	// plainProtocol is the websocket subprotocol of the KubeVirt streams.
+	plainProtocol = "plain.kubevirt.io"
+)
+
+// CheckVMAccess returns an error when the VM access is unknown.
+func CheckVMAccess(access string) error {
+	switch access {
+	case VMAccessRoute, VMAccessPortForward, VMAccessVSOCK:
+		return nil
+	}
+	return fmt.Errorf("unknown VM access %q, expected %s, %s or %s", access, VMAccessRoute, VMAccessPortForward, VMAccessVSOCK)
+}
+
+// tunneled returns true when ssh to the VMs is tunneled through the KubeVirt API.
+func tunneled(access string) bool {
+	return access == VMAccessPortForward || access == VMAccessVSOCK
+}
+
+// LoadSSHKey returns the private key of the run kept in dir, and generates it the first time,
+// so that a resumed run reaches the VMIs of the interrupted run.
+func LoadSSHKey(dir string) ([]byte, error) {
+	p := filepath.Join(dir, sshKeyFile)
+	if key, err := os.ReadFile(p); err == nil {
+		return key, nil
+	}
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("unable to generate the ssh key: %v", err)
+	}
+	block, err := ssh.MarshalPrivateKey(priv, "k8s-netperf")
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal the ssh key: %v", err)
+	}
+	key := pem.EncodeToMemory(block)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("unable to create run directory: %v", err)
+	}
+	if err := os.WriteFile(p, key, 0o600); err != nil {
+		return nil, fmt.Errorf("unable to write the ssh key: %v", err)
+	}
+	log.Debugf("Generated the ssh key of the run in %s", p)
+	return key, nil
+}
+
+// authorizedKey returns the public key injected in the VMs, of the run or of the user.
+func authorizedKey(perf *config.PerfScenarios) (string, error) {
+	if len(perf.VMSSHKey) > 0 {
+		signer, err := ssh.ParsePrivateKey(perf.VMSSHKey)
+		if err != nil {
+			return "", fmt.Errorf("unable to parse the ssh key of the run: %v", err)
+		}
+		return strings.TrimSpace(string(ssh.MarshalAuthorizedKey(signer.PublicKey()))), nil
+	}
+	dir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	key, err := os.ReadFile(fmt.Sprintf("%s/.ssh/id_rsa.pub", dir))
+	if err != nil {
+		return "", err
+	}
+	return string(key), nil
+}
+
+// TunnelConnect connects via ssh to the VMI through the KubeVirt API, with the key of the run.
+func TunnelConnect(perf *config.PerfScenarios, vmName string) (*goph.Client, error) {
+	signer, err := ssh.ParsePrivateKey(perf.VMSSHKey)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse the ssh key of the run: %v", err)
+	}
+	cfg := &ssh.ClientConfig{
+		User:            "fedora",
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		Timeout:         goph.DefaultTimeout,
+	}
+	for i := 0; i < retry; i++ {
+		var conn net.Conn
+		conn, err = dialVMI(perf, vmName)
+		if err == nil {
+			c, chans, reqs, herr := ssh.NewClientConn(conn, vmName, cfg)
+			if herr == nil {
+				return &goph.Client{Client: ssh.NewClient(c, chans, reqs)}, nil
+			}
+			if cerr := conn.Close(); cerr != nil {
+				log.Debugf("Error closing the ssh stream of %s: %v", vmName, cerr)
+			}
+			err = herr
+		}
+		log.Debug("Waiting for ssh access to be available")
+		log.Debug(err)
+		time.Sleep(10 * time.Second)
+	}
+	return nil, fmt.Errorf("unable to connect via ssh over %s after %d attempts: %v", perf.VMAccess, retry, err)
+}
+
+// dialVMI opens a stream to the ssh port of the VMI, through the port-forward or the VSOCK
+// subresource of the KubeVirt API.
+func dialVMI(perf *config.PerfScenarios, vmName string) (net.Conn, error) {
+	u, _, err := rest.DefaultServerUrlFor(&perf.RestConfig)
+	if err != nil {
+		return nil, err
+	}
+	subresource := "portforward/22/tcp"
+	if perf.VMAccess == VMAccessVSOCK {
+		subresource = "vsock"
+		u.RawQuery = fmt.Sprintf("port=%d&tls=false", vsockPort)
+	}
+	u.Path = path.Join(u.Path, "/apis/subresources.kubevirt.io/v1/namespaces", namespace, "virtualmachineinstances", vmName, subresource)
+	rt, holder, err := websocket.RoundTripperFor(&perf.RestConfig)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	ws, err := websocket.Negotiate(rt, holder, req, plainProtocol)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open a %s stream to the VMI %s: %v", perf.VMAccess, vmName, err)
+	}
+	return &wsConn{Conn: ws}, nil
+}
+
+// wsConn is a net.Conn over the binary messages of a KubeVirt websocket stream.
+type wsConn struct {
+	*gws.Conn
+	r  io.Reader
+	mu sync.Mutex
+}
+
+func (c *wsConn) Read(b []byte) (int, error) {
+	for {
+		if c.r == nil {
+			_, r, err := c.NextReader()
+			if err != nil {
+				return 0, err
+			}
+			c.r = r
+		}
+		n, err := c.r.Read(b)
+		if err == io.EOF {
+			c.r = nil
+			if n == 0 {
+				continue
+			}
+			err = nil
+		}
+		return n, err
+	}
+}
+
+func (c *wsConn) Write(b []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.WriteMessage(gws.BinaryMessage, b); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+func (c *wsConn) SetDeadline(t time.Time) error {
+	if err := c.SetReadDeadline(t); err != nil {
+		return err
+	}
+	return c.SetWriteDeadline(t)
+}
@@ -0,0 +1,81 @@
+package k8s
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/config"
+	gws "github.com/gorilla/websocket"
+	"golang.org/x/crypto/ssh"
+	"k8s.io/client-go/rest"
+)
+
+func TestLoadSSHKey(t *testing.T) {
+	dir := t.TempDir()
+	key, err := LoadSSHKey(dir)
+	if err != nil {
+		t.Fatalf("LoadSSHKey() error = %v", err)
+	}
+	again, err := LoadSSHKey(dir)
+	if err != nil || !bytes.Equal(key, again) {
+		t.Fatalf("LoadSSHKey() did not return the key of the run again: %v", err)
+	}
+	pub, err := authorizedKey(&config.PerfScenarios{VMSSHKey: key})
+	if err != nil {
+		t.Fatalf("authorizedKey() error = %v", err)
+	}
+	if _, _, _, _, err := ssh.ParseAuthorizedKey([]byte(pub)); err != nil || !strings.HasPrefix(pub, "ssh-ed25519 ") {
+		t.Fatalf("authorizedKey() = %q, not an ed25519 authorized key: %v", pub, err)
+	}
+}
+
+func TestDialVMI(t *testing.T) {
+	upgrader := gws.Upgrader{Subprotocols: []string{plainProtocol}}
+	var gotPath, gotQuery string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotQuery = r.URL.Path, r.URL.RawQuery
+		c, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer c.Close()
+		// Echo the stream, split in two messages.
+		_, msg, err := c.ReadMessage()
+		if err != nil {
+			return
+		}
+		_ = c.WriteMessage(gws.BinaryMessage, msg[:2])
+		_ = c.WriteMessage(gws.BinaryMessage, msg[2:])
+	}))
+	defer srv.Close()
+	testCases := []struct {
+		access string
+		path   string
+		query  string
+	}{
+		{VMAccessPortForward, "/apis/subresources.kubevirt.io/v1/namespaces/" + namespace + "/virtualmachineinstances/client-across/portforward/22/tcp", ""},
+		{VMAccessVSOCK, "/apis/subresources.kubevirt.io/v1/namespaces/" + namespace + "/virtualmachineinstances/client-across/vsock", "port=22&tls=false"},
+	}
+	for _, tc := range testCases {
+		perf := &config.PerfScenarios{VMAccess: tc.access, RestConfig: rest.Config{Host: srv.URL}}
+		conn, err := dialVMI(perf, "client-across")
+		if err != nil {
+			t.Fatalf("%s: dialVMI() error = %v", tc.access, err)
+		}
+		if gotPath != tc.path || gotQuery != tc.query {
+			t.Fatalf("%s: dialVMI() requested %s?%s, want %s?%s", tc.access, gotPath, gotQuery, tc.path, tc.query)
+		}
+		if _, err := conn.Write([]byte("SSH-2.0")); err != nil {
+			t.Fatalf("%s: Write() error = %v", tc.access, err)
+		}
+		got := make([]byte, 7)
+		if _, err := io.ReadFull(conn, got); err != nil || string(got) != "SSH-2.0" {
+			t.Fatalf("%s: Read() = %q, %v, want SSH-2.0", tc.access, got, err)
+		}
+		conn.Close()
+	}
+}
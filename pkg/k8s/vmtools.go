@@ -0,0 +1,228 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/config"
+	log "github.com/cloud-bulldozer/k8s-netperf/pkg/logging"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+	v1 "kubevirt.io/api/core/v1"
+)
+
+// How the VMs get netperf, super-netperf, iperf3 and uperf.
+const (
+	// VMToolsInstall installs the tools from the internet at boot.
+	VMToolsInstall = "install"
+	// VMToolsPrebuilt uses the tools baked in the VM image.
+	VMToolsPrebuilt = "prebuilt"
+	// VMToolsConfigMap copies the tools from the files of a ConfigMap, attached as a disk.
+	VMToolsConfigMap = "configmap"
+)
+
+// vmToolsSerial is the serial of the disk of the tools ConfigMap, the guest finds it by id.
+const vmToolsSerial = "netperftools"
+
+// vmDriverTools are the tools each driver runs on the client VM.
+var vmDriverTools = map[string][]string{
+	"netperf": {"netperf", "super-netperf"},
+	"iperf3":  {"iperf3"},
+	"uperf":   {"uperf"},
+}
+
+// CheckVMTools returns why the tools settings are invalid, nil when they are valid.
+func CheckVMTools(tools, configMap string) error {
+	switch tools {
+	case VMToolsInstall, VMToolsPrebuilt:
+		if configMap != "" {
+			return fmt.Errorf("a tools ConfigMap requires --vm-tools %s", VMToolsConfigMap)
+		}
+	case VMToolsConfigMap:
+		if _, _, ok := strings.Cut(configMap, "/"); !ok {
+			return fmt.Errorf("--vm-tools %s requires the ConfigMap, as namespace/name", VMToolsConfigMap)
+		}
+	default:
+		return fmt.Errorf("invalid VM tools %q, expected %s, %s or %s", tools, VMToolsInstall, VMToolsPrebuilt, VMToolsConfigMap)
+	}
+	return nil
+}
+
+// vmUserData returns the cloud-init user data of a VM: the fedora user with the ssh key, the
+// tools, the forwarding of the VSOCK port to ssh with vsock, and for a server the netperf,
+// iperf3 and uperf servers.
+func vmUserData(sshKey string, tools string, server bool, vsock bool) string {
+	data := fmt.Sprintf(`#cloud-config
+users:
+  - name: fedora
+    groups: sudo
+    shell: /bin/bash
+    sudo: ['ALL=(ALL) NOPASSWD:ALL']
+    ssh_deletekeys: false
+    ssh_authorized_keys:
+      - %s
+chpasswd:
+  list: |
+    fedora:fedora
+  expire: False
+runcmd:
+  - export HOME=/home/fedora
+`, strings.TrimSpace(sshKey))
+	// With VSOCK access, the VSOCK port is forwarded to the ssh port as soon as socat is there.
+	vsockForward := fmt.Sprintf("  - systemd-run --unit netperf-vsock socat VSOCK-LISTEN:%d,reuseaddr,fork TCP:localhost:22\n", vsockPort)
+	install := tools == VMToolsInstall || tools == ""
+	if vsock && install {
+		data += "  - until dnf install -y --nodocs socat --enablerepo=*; do sleep 3; done\n" + vsockForward
+	}
+	switch tools {
+	case VMToolsInstall, "":
+		data += `  - until dnf install -y --nodocs uperf iperf3 git ethtool automake gcc bc lksctp-tools-devel texinfo --enablerepo=*; do sleep 3; done
+  - git clone https://github.com/HewlettPackard/netperf.git
+  - cd netperf
+  - git reset --hard 3bc455b23f901dae377ca0a558e1e32aa56b31c4
+  - curl -o netperf.diff https://raw.githubusercontent.com/cloud-bulldozer/k8s-netperf/main/containers/netperf.diff
+  - git apply netperf.diff
+  - ./autogen.sh
+  - ./configure --enable-sctp=yes --enable-demo=yes
+  - make && make install
+  - cd
+`
+		if !server {
+			data += `  - curl -o /usr/bin/super-netperf https://raw.githubusercontent.com/cloud-bulldozer/k8s-netperf/main/containers/super-netperf
+  - chmod 0777 /usr/bin/super-netperf
+`
+		}
+	case VMToolsConfigMap:
+		data += fmt.Sprintf(`  - mkdir -p /mnt/netperf-tools
+  - mount -o ro /dev/disk/by-id/virtio-%s /mnt/netperf-tools
+  - install -m 0755 /mnt/netperf-tools/* /usr/local/bin/
+  - umount /mnt/netperf-tools
+`, vmToolsSerial)
+	}
+	if vsock && !install {
+		data += vsockForward
+	}
+	if server {
+		data += fmt.Sprintf(`  - uperf -s -v -P %d &
+  - iperf3 -s -p %d &
+  - netserver &
+`, UperfServerCtlPort, IperfServerCtlPort)
+	}
+	return data
+}
+
+// vmToolsDisk returns the disk and volume of the tools ConfigMap.
+func vmToolsDisk(configMap string) (v1.Disk, v1.Volume) {
+	disk := v1.Disk{
+		Name:   "netperf-tools",
+		Serial: vmToolsSerial,
+		DiskDevice: v1.DiskDevice{
+			Disk: &v1.DiskTarget{
+				Bus: "virtio",
+			},
+		},
+	}
+	volume := v1.Volume{
+		Name: "netperf-tools",
+		VolumeSource: v1.VolumeSource{
+			ConfigMap: &v1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: configMap},
+			},
+		},
+	}
+	return disk, volume
+}
+
+// serverReadinessProbe reports the server VM ready once netserver accepts connections,
+// which is the last server cloud-init starts.
+func serverReadinessProbe() *v1.Probe {
+	return &v1.Probe{
+		Handler: v1.Handler{
+			TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt32(NetperfServerCtlPort)},
+		},
+		PeriodSeconds: 10,
+	}
+}
+
+// vmToolsConfigMap copies the tools ConfigMap, namespace/name, in the namespace, the VMs can
+// only attach the ConfigMaps of their namespace, and returns its name. The copy is removed with
+// the namespace.
+func vmToolsConfigMap(client *kubernetes.Clientset, ref string) (string, error) {
+	ns, n, _ := strings.Cut(ref, "/")
+	if ns == namespace {
+		return n, nil
+	}
+	if _, err := client.CoreV1().ConfigMaps(namespace).Get(context.TODO(), n, metav1.GetOptions{}); err == nil {
+		return n, nil
+	}
+	cm, err := client.CoreV1().ConfigMaps(ns).Get(context.TODO(), n, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("😥 Unable to get the VM tools ConfigMap %s: %v", ref, err)
+	}
+	log.Infof("🧰 Copying the VM tools ConfigMap %s", ref)
+	_, err = client.CoreV1().ConfigMaps(namespace).Create(context.TODO(), &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: n},
+		Data:       cm.Data,
+		BinaryData: cm.BinaryData,
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("😥 Unable to copy the VM tools ConfigMap %s: %v", ref, err)
+	}
+	return n, nil
+}
+
+// WaitForVMTools waits for cloud-init to finish on the VM, and checks the tools of the drivers
+// are installed. cloud-init reports when it is done, instead of polling for the tools.
+func WaitForVMTools(vm config.VMExecutor, drivers ...string) error {
+	log.Info("⏰ Waiting for cloud-init to finish on the VMI")
+	var status []byte
+	var err error
+	for i := 0; i < retry; i++ {
+		// cloud-init exits non-zero when a module failed, the tools tell whether it matters.
+		status, err = vm.Run("cloud-init status --wait --long; true")
+		if err == nil {
+			break
+		}
+		log.Debugf("Waiting for the VMI to accept commands (%d/%d): %v", i+1, retry, err)
+		time.Sleep(10 * time.Second)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to run commands on the VMI: %v", err)
+	}
+	var tools []string
+	for _, d := range drivers {
+		tools = append(tools, vmDriverTools[d]...)
+	}
+	if len(tools) == 0 {
+		return nil
+	}
+	out, err := vm.Run("command -v " + strings.Join(tools, " ") + "; true")
+	if err != nil {
+		return fmt.Errorf("unable to look for %s on the VMI: %v", strings.Join(tools, ", "), err)
+	}
+	if missing := missingTools(tools, string(out)); len(missing) > 0 {
+		return fmt.Errorf("%s not found on the VMI, cloud-init: %s", strings.Join(missing, ", "), strings.TrimSpace(string(status)))
+	}
+	log.Debugf("Found %s on the VMI", strings.Join(tools, ", "))
+	return nil
+}
+
+// missingTools returns the tools which are not in the output of command -v.
+func missingTools(tools []string, out string) []string {
+	found := map[string]bool{}
+	for _, line := range strings.Split(out, "\n") {
+		found[path.Base(strings.TrimSpace(line))] = true
+	}
+	var missing []string
+	for _, t := range tools {
+		if !found[t] {
+			missing = append(missing, t)
+		}
+	}
+	return missing
+}
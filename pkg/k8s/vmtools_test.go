@@ -0,0 +1,108 @@
+package k8s
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestVMUserData(t *testing.T) {
+	tests := []struct {
+		name    string
+		tools   string
+		server  bool
+		vsock   bool
+		want    []string
+		notWant []string
+	}{
+		{"install client", VMToolsInstall, false, false, []string{"dnf install", "super-netperf"}, []string{"netserver &"}},
+		{"install server", VMToolsInstall, true, false, []string{"dnf install", "netserver &"}, []string{"super-netperf", "VSOCK"}},
+		{"prebuilt client", VMToolsPrebuilt, false, false, []string{"ssh-ed25519 AAAA"}, []string{"dnf", "git clone", "curl", "mount"}},
+		{"prebuilt server", VMToolsPrebuilt, true, false, []string{"iperf3 -s -p", "netserver &"}, []string{"dnf", "curl"}},
+		{"configmap client", VMToolsConfigMap, false, false, []string{"/dev/disk/by-id/virtio-" + vmToolsSerial, "/usr/local/bin/"}, []string{"dnf", "curl"}},
+		{"install client over VSOCK", VMToolsInstall, false, true, []string{"dnf install -y --nodocs socat", "socat VSOCK-LISTEN:22,reuseaddr,fork TCP:localhost:22"}, nil},
+		{"prebuilt client over VSOCK", VMToolsPrebuilt, false, true, []string{"socat VSOCK-LISTEN:22"}, []string{"dnf"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := vmUserData("ssh-ed25519 AAAA\n", tt.tools, tt.server, tt.vsock)
+			if !strings.HasPrefix(data, "#cloud-config\n") {
+				t.Fatalf("the user data is not a cloud-config:\n%s", data)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(data, w) {
+					t.Fatalf("the user data does not contain %q:\n%s", w, data)
+				}
+			}
+			for _, w := range tt.notWant {
+				if strings.Contains(data, w) {
+					t.Fatalf("the user data contains %q:\n%s", w, data)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckVMTools(t *testing.T) {
+	tests := []struct {
+		tools     string
+		configMap string
+		wantErr   bool
+	}{
+		{VMToolsInstall, "", false},
+		{VMToolsPrebuilt, "", false},
+		{VMToolsConfigMap, "tools/netperf-tools", false},
+		{VMToolsConfigMap, "", true},
+		{VMToolsConfigMap, "netperf-tools", true},
+		{VMToolsPrebuilt, "tools/netperf-tools", true},
+		{"download", "", true},
+	}
+	for _, tt := range tests {
+		if err := CheckVMTools(tt.tools, tt.configMap); (err != nil) != tt.wantErr {
+			t.Fatalf("CheckVMTools(%q, %q) error = %v, wantErr %t", tt.tools, tt.configMap, err, tt.wantErr)
+		}
+	}
+}
+
+// fakeVM answers the commands run on the VM.
+type fakeVM struct {
+	out map[string]string
+	ran []string
+}
+
+func (f *fakeVM) Run(command string) ([]byte, error) {
+	f.ran = append(f.ran, command)
+	for prefix, out := range f.out {
+		if strings.HasPrefix(command, prefix) {
+			return []byte(out), nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeVM) Close() error {
+	return nil
+}
+
+func TestWaitForVMTools(t *testing.T) {
+	vm := &fakeVM{out: map[string]string{
+		"cloud-init status": "status: done",
+		"command -v":        "/usr/local/bin/netperf\n/usr/local/bin/super-netperf\n/usr/bin/iperf3\n",
+	}}
+	if err := WaitForVMTools(vm, "netperf", "iperf3"); err != nil {
+		t.Fatalf("WaitForVMTools() = %v", err)
+	}
+	if len(vm.ran) != 2 || !strings.Contains(vm.ran[0], "--wait") {
+		t.Fatalf("WaitForVMTools() ran %v, want cloud-init status --wait then command -v", vm.ran)
+	}
+	err := WaitForVMTools(vm, "netperf", "uperf")
+	if err == nil || !strings.Contains(err.Error(), "uperf not found") || !strings.Contains(err.Error(), "status: done") {
+		t.Fatalf("WaitForVMTools() = %v, want uperf not found with the cloud-init status", err)
+	}
+}
+
+func TestMissingTools(t *testing.T) {
+	got := missingTools([]string{"netperf", "super-netperf", "uperf"}, "/usr/local/bin/netperf\n")
+	if strings.Join(got, ",") != "super-netperf,uperf" {
+		t.Fatalf("missingTools() = %v, want super-netperf and uperf", got)
+	}
+}
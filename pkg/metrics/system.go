@@ -16,6 +16,8 @@ import (
 type NodeInfo struct {
 	IP       string `json:"ip"`
 	NodeName string `json:"nodeName"`
+	// Selection is how the node was selected with --client-node/--server-node or their selectors, empty if not pinned
+	Selection string `json:"selection,omitempty"`
 	corev1.NodeSystemInfo
 }
 
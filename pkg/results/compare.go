@@ -0,0 +1,144 @@
+package result
+
+import (
+	"fmt"
+	"strconv"
+
+	math "github.com/aclements/go-moremath/stats"
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/logging"
+)
+
+// Statistical tests supported by Compare
+const (
+	WelchTTest = "welch"
+	MannWhitU  = "utest"
+)
+
+// Comparison describes the difference of one metric of a test between two runs.
+type Comparison struct {
+	Base   Data
+	New    Data
+	Metric string
+	Unit   string
+	MeanA  float64
+	MeanB  float64
+	Delta  float64 // %diff of MeanB against MeanA
+	P      float64
+	// Err is set when the statistical test could not be computed, e.g. with a single sample.
+	Err error
+}
+
+// Significant returns true when the p-value of the comparison is below alpha.
+func (c Comparison) Significant(alpha float64) bool {
+	return c.Err == nil && c.P < alpha
+}
+
+// significanceMarker returns the benchstat-like marker for the p-value.
+func significanceMarker(c Comparison, alpha float64) string {
+	switch {
+	case c.Err != nil:
+		return "?"
+	case c.P < alpha/50:
+		return "***"
+	case c.P < alpha/5:
+		return "**"
+	case c.P < alpha:
+		return "*"
+	default:
+		return "~"
+	}
+}
+
+// pValue runs the requested statistical test on the two sets of samples.
+func pValue(a, b []float64, method string) (float64, error) {
+	switch method {
+	case MannWhitU:
+		r, err := math.MannWhitneyUTest(a, b, math.LocationDiffers)
+		if err != nil {
+			return 0, err
+		}
+		return r.P, nil
+	case WelchTTest:
+		r, err := math.TwoSampleWelchTTest(math.Sample{Xs: a}, math.Sample{Xs: b}, math.LocationDiffers)
+		if err != nil {
+			return 0, err
+		}
+		return r.P, nil
+	default:
+		return 0, fmt.Errorf("unknown statistical test: %s", method)
+	}
+}
+
+func compareSamples(base Data, cur Data, metric string, unit string, a, b []float64, method string) Comparison {
+	c := Comparison{Base: base, New: cur, Metric: metric, Unit: unit}
+	c.MeanA, _ = Average(a)
+	c.MeanB, _ = Average(b)
+	if c.MeanA != 0 {
+		c.Delta = (c.MeanB - c.MeanA) / c.MeanA * 100
+	}
+	c.P, c.Err = pValue(a, b, method)
+	return c
+}
+
+// compareKey returns the identity the tests of two runs are matched by. With ignoreNetwork,
+// the network and the runtime class of the tests are left out, so that the same tests run
+// over another network, e.g. a UDN and the default network, are compared.
+func compareKey(r Data, ignoreNetwork bool) string {
+	if ignoreNetwork {
+		r.UdnInfo, r.BridgeInfo, r.SriovInfo, r.MacvlanInfo, r.LocalnetInfo, r.RuntimeClass = "", "", "", "", "", ""
+	}
+	return TestID(r)
+}
+
+// Compare matches the results of two runs by test identity and compares the raw
+// throughput samples, and the 99%tile latency samples for RR profiles, with the given method.
+// With ignoreNetwork, the tests are matched regardless of their network, see compareKey.
+// returns the comparisons and the results of b which have no match in a.
+func Compare(a, b ScenarioResults, method string, ignoreNetwork bool) ([]Comparison, []Data, error) {
+	if method != WelchTTest && method != MannWhitU {
+		return nil, nil, fmt.Errorf("unknown statistical test: %s", method)
+	}
+	base := make(map[string]Data)
+	for _, r := range a.Results {
+		if !r.Failed() {
+			base[compareKey(r, ignoreNetwork)] = r
+		}
+	}
+	var cmp []Comparison
+	var unmatched []Data
+	for _, r := range b.Results {
+		if len(r.Driver) < 1 || r.Failed() {
+			continue
+		}
+		br, ok := base[compareKey(r, ignoreNetwork)]
+		if !ok {
+			unmatched = append(unmatched, r)
+			continue
+		}
+		cmp = append(cmp, compareSamples(br, r, "Throughput", r.Metric, br.ThroughputSummary, r.ThroughputSummary, method))
+		if hasLatency(r.Profile) {
+			cmp = append(cmp, compareSamples(br, r, "99%tile Latency", "usec", br.LatencySummary, r.LatencySummary, method))
+		}
+	}
+	return cmp, unmatched, nil
+}
+
+// ShowComparison presents the comparisons to the user via stdout.
+// Markers: *** p < alpha/50, ** p < alpha/5, * p < alpha, ~ not significant, ? not enough samples.
+func ShowComparison(cmp []Comparison, unmatched []Data, alpha float64) {
+	table := initTable([]string{"Driver", "Scenario", "Parallelism", "Host Network", "Virt mode", "Path", "Runtime Class", "Service", "Message Size", "Burst", "Same node", "Metric", "Base", "New", "Delta", "P-value", ""})
+	for _, c := range cmp {
+		r := c.New
+		p := "n/a"
+		if c.Err == nil {
+			p = fmt.Sprintf("%.4f", c.P)
+		} else {
+			logging.Debugf("Unable to compute significance for %s %s: %v", r.Driver, r.Profile, c.Err)
+		}
+		table.Append([]string{r.Driver, r.Profile, strconv.Itoa(r.Parallelism), strconv.FormatBool(r.HostNetwork), strconv.FormatBool(r.Virt), PathLabel(r), RuntimeLabel(r), strconv.FormatBool(r.Service), strconv.Itoa(r.MessageSize), strconv.Itoa(r.Burst), strconv.FormatBool(r.SameNode), c.Metric, fmt.Sprintf("%f (%s)", c.MeanA, c.Unit), fmt.Sprintf("%f (%s)", c.MeanB, c.Unit), fmt.Sprintf("%+.2f%%", c.Delta), p, significanceMarker(c, alpha)})
+	}
+	table.Render()
+	for _, r := range unmatched {
+		logging.Warnf("No matching test in the base result for %s %s (parallelism %d, message size %d)", r.Driver, r.Profile, r.Parallelism, r.MessageSize)
+	}
+}
@@ -0,0 +1,87 @@
+package result
+
+import (
+	"testing"
+
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/config"
+)
+
+func compareData(profile string, tput []float64, lat []float64) Data {
+	return Data{
+		Driver:            "netperf",
+		Config:            config.Config{Profile: profile, Parallelism: 1, MessageSize: 1024, Duration: 10},
+		ThroughputSummary: tput,
+		LatencySummary:    lat,
+	}
+}
+
+func TestCompare(t *testing.T) {
+	a := ScenarioResults{Results: []Data{
+		compareData("TCP_STREAM", []float64{100, 101, 99, 100, 100}, []float64{0}),
+		compareData("TCP_RR", []float64{5000, 5010, 4990}, []float64{50, 51, 49}),
+	}}
+	b := ScenarioResults{Results: []Data{
+		compareData("TCP_STREAM", []float64{80, 81, 79, 80, 80}, []float64{0}),
+		compareData("TCP_RR", []float64{5005, 4995, 5000}, []float64{50, 49, 51}),
+		compareData("UDP_STREAM", []float64{10}, []float64{0}),
+	}}
+	for _, method := range []string{WelchTTest, MannWhitU} {
+		cmp, unmatched, err := Compare(a, b, method, false)
+		if err != nil {
+			t.Fatalf("Compare(%s) returned error: %v", method, err)
+		}
+		// TCP_STREAM throughput, TCP_RR throughput and latency
+		if len(cmp) != 3 {
+			t.Fatalf("Compare(%s) returned %d comparisons, want 3", method, len(cmp))
+		}
+		if len(unmatched) != 1 || unmatched[0].Profile != "UDP_STREAM" {
+			t.Fatalf("Compare(%s) returned unmatched %v, want UDP_STREAM", method, unmatched)
+		}
+		if cmp[0].Delta != -20 {
+			t.Fatalf("Compare(%s) returned delta %f, want -20", method, cmp[0].Delta)
+		}
+		if !cmp[0].Significant(0.05) {
+			t.Fatalf("Compare(%s) reported the throughput drop as not significant (p=%f)", method, cmp[0].P)
+		}
+		if method == WelchTTest && cmp[1].Significant(0.05) {
+			t.Fatalf("Compare(%s) reported unchanged throughput as significant (p=%f)", method, cmp[1].P)
+		}
+	}
+	if _, _, err := Compare(a, b, "ks", false); err == nil {
+		t.Fatal("Compare accepted an unknown statistical test")
+	}
+}
+
+func TestCompareSingleSample(t *testing.T) {
+	a := ScenarioResults{Results: []Data{compareData("TCP_STREAM", []float64{100}, []float64{0})}}
+	b := ScenarioResults{Results: []Data{compareData("TCP_STREAM", []float64{90}, []float64{0})}}
+	cmp, _, err := Compare(a, b, WelchTTest, false)
+	if err != nil {
+		t.Fatalf("Compare returned error: %v", err)
+	}
+	if len(cmp) != 1 || cmp[0].Err == nil || cmp[0].Significant(0.05) {
+		t.Fatalf("Compare returned %+v, want an inconclusive comparison", cmp)
+	}
+	if m := significanceMarker(cmp[0], 0.05); m != "?" {
+		t.Fatalf("significanceMarker returned %q, want ?", m)
+	}
+}
+
+func TestCompareIgnoreNetwork(t *testing.T) {
+	base := compareData("TCP_STREAM", []float64{100, 101, 99}, []float64{0})
+	udn := compareData("TCP_STREAM", []float64{90, 91, 89}, []float64{0})
+	udn.UdnInfo = "layer2"
+	a := ScenarioResults{Results: []Data{base}}
+	b := ScenarioResults{Results: []Data{udn}}
+	cmp, unmatched, err := Compare(a, b, WelchTTest, false)
+	if err != nil || len(cmp) != 0 || len(unmatched) != 1 {
+		t.Fatalf("Compare() = %v, %v, %v, want the UDN test unmatched", cmp, unmatched, err)
+	}
+	cmp, unmatched, err = Compare(a, b, WelchTTest, true)
+	if err != nil || len(cmp) != 1 || len(unmatched) != 0 {
+		t.Fatalf("Compare(ignoreNetwork) = %v, %v, %v, want the UDN test matched to the default network", cmp, unmatched, err)
+	}
+	if cmp[0].Base.UdnInfo != "" || cmp[0].New.UdnInfo != "layer2" {
+		t.Fatalf("Compare(ignoreNetwork) matched %+v, want the results unchanged", cmp[0])
+	}
+}
@@ -0,0 +1,110 @@
+package result
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/sample"
+)
+
+// Aggregate combines the concurrent samples of the endpoints of an incast or fan-out into
+// one sample: throughput and retransmits add up, the average and median latencies and the
+// loss are averaged, and the 99%tile latency is the worst of the endpoints.
+func Aggregate(samples []sample.Sample) sample.Sample {
+	var a sample.Sample
+	if len(samples) == 0 {
+		return a
+	}
+	a.Metric = samples[0].Metric
+	a.Driver = samples[0].Driver
+	for _, s := range samples {
+		a.Throughput += s.Throughput
+		a.Retransmits += s.Retransmits
+		a.Latency += s.Latency
+		a.Latency50ptile += s.Latency50ptile
+		a.LossPercent += s.LossPercent
+		a.Latency99ptile = max(a.Latency99ptile, s.Latency99ptile)
+	}
+	n := float64(len(samples))
+	a.Latency /= n
+	a.Latency50ptile /= n
+	a.LossPercent /= n
+	return a
+}
+
+// JainIndex returns Jain's fairness index of the values, (sum x)^2 / (n * sum x^2), from 1/n
+// when one endpoint gets everything to 1 when all the endpoints get the same share.
+func JainIndex(vals []float64) float64 {
+	var sum, sq float64
+	for _, v := range vals {
+		sum += v
+		sq += v * v
+	}
+	if sq == 0 {
+		return 0
+	}
+	return sum * sum / (float64(len(vals)) * sq)
+}
+
+// TopologyLabel describes the topology of the test for the tables, e.g. incast 4:1 or pairs 8,
+// and the live migration of a migrate test.
+func TopologyLabel(r Data) string {
+	switch {
+	case r.Incast > 0:
+		return fmt.Sprintf("%s %d:1", r.Topology(), r.Incast)
+	case r.Fanout > 0:
+		return fmt.Sprintf("%s 1:%d", r.Topology(), r.Fanout)
+	case r.Pairs > 0 && r.Spread:
+		return fmt.Sprintf("%s %d spread", r.Topology(), r.Pairs)
+	case r.Pairs > 0:
+		return fmt.Sprintf("%s %d", r.Topology(), r.Pairs)
+	case r.Migrate != "":
+		return fmt.Sprintf("migrate %s after %ds", r.Migrate, r.MigrateAfter)
+	}
+	return ""
+}
+
+// endpointThroughput returns the throughput of each endpoint averaged over the samples.
+func endpointThroughput(r Data) []float64 {
+	avg := make([]float64, len(r.Endpoints))
+	for _, smp := range r.EndpointThroughputSummary {
+		for i := range avg {
+			if i < len(smp) {
+				avg[i] += smp[i]
+			}
+		}
+	}
+	for i := range avg {
+		avg[i] /= float64(len(r.EndpointThroughputSummary))
+	}
+	return avg
+}
+
+// ShowGroupResults presents the throughput of each client of the incast tests, of each
+// server of the fan-out tests and of each pair of the pairs tests, with its share of the
+// aggregated throughput and the average Jain's fairness index of the test, via stdout.
+func ShowGroupResults(s ScenarioResults) {
+	rows := 0
+	table := initTable([]string{"Result Type", "Driver", "Scenario", "Topology", "Parallelism", "Message Size", "Endpoint", "Node", "Avg value", "Share", "Jain's Index"})
+	for _, r := range s.Results {
+		if len(r.EndpointThroughputSummary) == 0 {
+			continue
+		}
+		total, _ := Average(r.ThroughputSummary)
+		jain := "n/a"
+		if j, err := Average(r.FairnessSummary); err == nil {
+			jain = fmt.Sprintf("%.3f", j)
+		}
+		for i, tput := range endpointThroughput(r) {
+			share := "n/a"
+			if total > 0 {
+				share = fmt.Sprintf("%.1f%%", tput/total*100)
+			}
+			table.Append([]string{"🔀 Endpoint Results", r.Driver, r.Profile, TopologyLabel(r), strconv.Itoa(r.Parallelism), strconv.Itoa(r.MessageSize), strconv.Itoa(i + 1), r.Endpoints[i], fmt.Sprintf("%f (%s)", tput, r.Metric), share, jain})
+			rows++
+		}
+	}
+	if rows > 0 {
+		table.Render()
+	}
+}
@@ -0,0 +1,69 @@
+package result
+
+import (
+	"testing"
+
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/config"
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/sample"
+)
+
+func TestAggregate(t *testing.T) {
+	a := Aggregate([]sample.Sample{
+		{Throughput: 3000, Retransmits: 2, Latency: 10, Latency50ptile: 8, Latency99ptile: 40, LossPercent: 1, Metric: "Mb/s", Driver: "netperf"},
+		{Throughput: 5000, Retransmits: 4, Latency: 20, Latency50ptile: 12, Latency99ptile: 90, LossPercent: 3, Metric: "Mb/s", Driver: "netperf"},
+	})
+	want := sample.Sample{Throughput: 8000, Retransmits: 6, Latency: 15, Latency50ptile: 10, Latency99ptile: 90, LossPercent: 2, Metric: "Mb/s", Driver: "netperf"}
+	if a != want {
+		t.Fatalf("Aggregate returned %+v, want %+v", a, want)
+	}
+	if a := Aggregate(nil); a != (sample.Sample{}) {
+		t.Fatalf("Aggregate of no sample returned %+v", a)
+	}
+}
+
+func TestTopologyLabel(t *testing.T) {
+	testCases := []struct {
+		cfg  config.Config
+		want string
+	}{
+		{cfg: config.Config{}, want: ""},
+		{cfg: config.Config{Incast: 4}, want: "incast 4:1"},
+		{cfg: config.Config{Fanout: 3}, want: "fanout 1:3"},
+		{cfg: config.Config{Pairs: 8}, want: "pairs 8"},
+		{cfg: config.Config{Pairs: 8, Spread: true}, want: "pairs 8 spread"},
+		{cfg: config.Config{Migrate: config.MigrateServer, MigrateAfter: 20}, want: "migrate server after 20s"},
+	}
+	for _, tc := range testCases {
+		if got := TopologyLabel(Data{Config: tc.cfg}); got != tc.want {
+			t.Fatalf("TopologyLabel(%+v) = %q, want %q", tc.cfg, got, tc.want)
+		}
+	}
+}
+
+func TestEndpointThroughput(t *testing.T) {
+	r := Data{
+		Endpoints:                 []string{"a", "b"},
+		EndpointThroughputSummary: [][]float64{{100, 300}, {200, 500}},
+	}
+	got := endpointThroughput(r)
+	if len(got) != 2 || got[0] != 150 || got[1] != 400 {
+		t.Fatalf("endpointThroughput returned %v, want [150 400]", got)
+	}
+}
+
+func TestJainIndex(t *testing.T) {
+	testCases := []struct {
+		vals []float64
+		want float64
+	}{
+		{vals: []float64{100, 100, 100, 100}, want: 1},
+		{vals: []float64{400, 0, 0, 0}, want: 0.25},
+		{vals: []float64{300, 100}, want: 0.8},
+		{vals: []float64{0, 0}, want: 0},
+	}
+	for _, tc := range testCases {
+		if got := JainIndex(tc.vals); got != tc.want {
+			t.Fatalf("JainIndex(%v) = %f, want %f", tc.vals, got, tc.want)
+		}
+	}
+}
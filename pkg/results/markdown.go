@@ -0,0 +1,202 @@
+package result
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// mdColumn describes one column of a markdown table. Optional columns are
+// dropped when every row in the table holds the scenario default.
+type mdColumn struct {
+	header   string
+	value    func(r Data) string
+	optional bool
+	isSet    func(r Data) bool
+}
+
+// hasLatency returns true when the profile reports a meaningful P99 latency.
+func hasLatency(profile string) bool {
+	return strings.Contains(profile, "RR") || strings.Contains(profile, "STREAM_LAT")
+}
+
+// fmtDelta returns the %diff of cur against base, or "n/a" when there is no baseline.
+func fmtDelta(cur float64, base float64, ok bool) string {
+	if !ok || base == 0 {
+		return "n/a"
+	}
+	return fmt.Sprintf("%+.1f%%", (cur-base)/base*100)
+}
+
+// networkColumns are the columns of the network of the tests, shown in the markdown and
+// stdout tables only when a test ran over such a network, see visibleColumns.
+var networkColumns = []mdColumn{
+	{header: "UDN Info", value: func(r Data) string { return r.UdnInfo }, optional: true, isSet: func(r Data) bool { return r.UdnInfo != "" }},
+	{header: "Bridge Info", value: func(r Data) string { return r.BridgeInfo }, optional: true, isSet: func(r Data) bool { return r.BridgeInfo != "" }},
+	{header: "SR-IOV Info", value: func(r Data) string { return r.SriovInfo }, optional: true, isSet: func(r Data) bool { return r.SriovInfo != "" }},
+	{header: "Macvlan Info", value: func(r Data) string { return r.MacvlanInfo }, optional: true, isSet: func(r Data) bool { return r.MacvlanInfo != "" }},
+	{header: "Localnet Info", value: func(r Data) string { return r.LocalnetInfo }, optional: true, isSet: func(r Data) bool { return r.LocalnetInfo != "" }},
+}
+
+func markdownColumns(profile string, baseline map[string]Data) []mdColumn {
+	cols := []mdColumn{
+		{header: "Driver", value: func(r Data) string { return r.Driver }},
+		{header: "Parallelism", value: func(r Data) string { return strconv.Itoa(r.Parallelism) }},
+		{header: "Message Size", value: func(r Data) string { return strconv.Itoa(r.MessageSize) }},
+		{header: "Burst", value: func(r Data) string { return strconv.Itoa(r.Burst) }, optional: true, isSet: func(r Data) bool { return r.Burst > 0 }},
+		{header: "Host Network", value: func(r Data) string { return strconv.FormatBool(r.HostNetwork) }, optional: true, isSet: func(r Data) bool { return r.HostNetwork }},
+		{header: "Virt mode", value: func(r Data) string { return strconv.FormatBool(r.Virt) }, optional: true, isSet: func(r Data) bool { return r.Virt }},
+		{header: "Path", value: PathLabel, optional: true, isSet: func(r Data) bool { return r.Path == PathPodToVM || r.Path == PathVMToPod }},
+		{header: "Runtime Class", value: RuntimeLabel, optional: true, isSet: func(r Data) bool { return r.RuntimeClass != "" }},
+		{header: "Service", value: func(r Data) string { return strconv.FormatBool(r.Service) }, optional: true, isSet: func(r Data) bool { return r.Service }},
+		{header: "External Server", value: func(r Data) string { return strconv.FormatBool(r.ExternalServer) }, optional: true, isSet: func(r Data) bool { return r.ExternalServer }},
+		{header: "Same node", value: func(r Data) string { return strconv.FormatBool(r.SameNode) }, optional: true, isSet: func(r Data) bool { return r.SameNode }},
+		{header: "Across AZ", value: func(r Data) string { return strconv.FormatBool(r.AcrossAZ) }, optional: true, isSet: func(r Data) bool { return r.AcrossAZ }},
+	}
+	cols = append(cols, networkColumns...)
+	cols = append(cols, []mdColumn{
+		{header: "Topology", value: func(r Data) string { return TopologyLabel(r) }, optional: true, isSet: func(r Data) bool { return r.Topology() != "" }},
+		{header: "Duration", value: func(r Data) string { return strconv.Itoa(r.Duration) }},
+		{header: "Samples", value: func(r Data) string { return strconv.Itoa(r.Samples) }},
+		{header: "Avg value", value: func(r Data) string {
+			avg, _ := Average(r.ThroughputSummary)
+			return fmt.Sprintf("%.2f %s", avg, r.Metric)
+		}},
+		{header: "95% CI", value: func(r Data) string {
+			if r.Samples < 2 {
+				return "n/a"
+			}
+			_, lo, hi := ConfidenceInterval(r.ThroughputSummary, 0.95)
+			return fmt.Sprintf("%.2f-%.2f", lo, hi)
+		}},
+	}...)
+	if baseline != nil {
+		cols = append(cols, mdColumn{header: "Δ Avg", value: func(r Data) string {
+			b, ok := baseline[TestID(r)]
+			cur, _ := Average(r.ThroughputSummary)
+			base, _ := Average(b.ThroughputSummary)
+			return fmtDelta(cur, base, ok)
+		}})
+	}
+	if hasLatency(profile) {
+		cols = append(cols, mdColumn{header: "Avg 99%tile", value: func(r Data) string {
+			p99, _ := Average(r.LatencySummary)
+			return fmt.Sprintf("%.2f usec", p99)
+		}})
+		if baseline != nil {
+			cols = append(cols, mdColumn{header: "Δ 99%tile", value: func(r Data) string {
+				b, ok := baseline[TestID(r)]
+				cur, _ := Average(r.LatencySummary)
+				base, _ := Average(b.LatencySummary)
+				return fmtDelta(cur, base, ok)
+			}})
+		}
+	}
+	return cols
+}
+
+// visibleColumns drops the optional columns where no row differs from the default.
+func visibleColumns(cols []mdColumn, rows []Data) []mdColumn {
+	var visible []mdColumn
+	for _, c := range cols {
+		if !c.optional {
+			visible = append(visible, c)
+			continue
+		}
+		for _, r := range rows {
+			if c.isSet(r) {
+				visible = append(visible, c)
+				break
+			}
+		}
+	}
+	return visible
+}
+
+// columnHeaders returns the headers of the columns.
+func columnHeaders(cols []mdColumn) []string {
+	header := make([]string, len(cols))
+	for i, c := range cols {
+		header[i] = c.header
+	}
+	return header
+}
+
+// columnValues returns the values of the columns for the row.
+func columnValues(cols []mdColumn, r Data) []string {
+	line := make([]string, len(cols))
+	for i, c := range cols {
+		line[i] = c.value(r)
+	}
+	return line
+}
+
+// RenderMarkdown writes the results as compact markdown tables, one per profile, to w.
+// When a baseline is provided, each row carries the %diff against the matching baseline test.
+func RenderMarkdown(w io.Writer, s ScenarioResults, baseline *ScenarioResults) error {
+	var base map[string]Data
+	if baseline != nil {
+		base = make(map[string]Data)
+		for _, b := range baseline.Results {
+			if !b.Failed() {
+				base[TestID(b)] = b
+			}
+		}
+	}
+	var profiles []string
+	groups := make(map[string][]Data)
+	for _, r := range s.Results {
+		if len(r.Driver) < 1 {
+			continue
+		}
+		if _, ok := groups[r.Profile]; !ok {
+			profiles = append(profiles, r.Profile)
+		}
+		groups[r.Profile] = append(groups[r.Profile], r)
+	}
+	if _, err := fmt.Fprintf(w, "## k8s-netperf results\n\n"); err != nil {
+		return err
+	}
+	if s.Version != "" {
+		if _, err := fmt.Fprintf(w, "Version: `%s` (`%s`)", s.Version, s.GitCommit); err != nil {
+			return err
+		}
+		if s.OCPVersion != "" {
+			if _, err := fmt.Fprintf(w, ", Cluster: `%s`", s.OCPVersion); err != nil {
+				return err
+			}
+		}
+		if s.ImageDigest != "" {
+			if _, err := fmt.Fprintf(w, ", Image: `%s` (`%s`)", s.Image, s.ImageDigest); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "\n\n"); err != nil {
+			return err
+		}
+	}
+	for _, profile := range profiles {
+		rows := groups[profile]
+		cols := visibleColumns(markdownColumns(profile, base), rows)
+		if _, err := fmt.Fprintf(w, "### %s\n\n", profile); err != nil {
+			return err
+		}
+		table := tablewriter.NewWriter(w)
+		table.SetHeader(columnHeaders(cols))
+		table.SetAutoFormatHeaders(false)
+		table.SetAutoWrapText(false)
+		table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
+		table.SetCenterSeparator("|")
+		for _, r := range rows {
+			table.Append(columnValues(cols, r))
+		}
+		table.Render()
+		if _, err := fmt.Fprintln(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package result
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/config"
+)
+
+func TestRenderMarkdownCollapsesDefaultColumns(t *testing.T) {
+	r := Data{
+		Config:            config.Config{Profile: "TCP_STREAM", Parallelism: 1, MessageSize: 1024, Duration: 10, Samples: 1},
+		Driver:            "netperf",
+		Metric:            "Mb/s",
+		ThroughputSummary: []float64{110},
+	}
+	base := r
+	base.ThroughputSummary = []float64{100}
+	var buf bytes.Buffer
+	err := RenderMarkdown(&buf, ScenarioResults{Results: []Data{r}}, &ScenarioResults{Results: []Data{base}})
+	if err != nil {
+		t.Fatalf("RenderMarkdown returned unexpected error: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "### TCP_STREAM") {
+		t.Fatalf("missing profile heading:\n%s", out)
+	}
+	for _, col := range []string{"UDN Info", "Bridge Info", "SR-IOV Info", "Macvlan Info", "Localnet Info", "Host Network"} {
+		if strings.Contains(out, col) {
+			t.Fatalf("column %q should be collapsed:\n%s", col, out)
+		}
+	}
+	if !strings.Contains(out, "+10.0%") {
+		t.Fatalf("missing baseline delta:\n%s", out)
+	}
+}
@@ -0,0 +1,144 @@
+package result
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/logging"
+	stats "github.com/montanaflynn/stats"
+)
+
+// MeshResult is the outcome of a test between one ordered pair of nodes of the mesh.
+type MeshResult struct {
+	Driver      string  `json:"driver"`
+	Profile     string  `json:"profile"`
+	MessageSize int     `json:"messageSize"`
+	Parallelism int     `json:"parallelism"`
+	Client      string  `json:"client"`
+	Server      string  `json:"server"`
+	Value       float64 `json:"value"`
+	Metric      string  `json:"metric"`
+	// Median of the pairs of the same test, and %diff of Value against it.
+	Median    float64 `json:"median"`
+	Deviation float64 `json:"deviation"`
+	Outlier   bool    `json:"outlier"`
+	// Reason is set when the test between the pair failed.
+	Reason string `json:"reason,omitempty"`
+}
+
+// Failed returns true when the test between the pair did not complete.
+func (r MeshResult) Failed() bool {
+	return r.Reason != ""
+}
+
+// MeshMetric returns the metric the mesh reports for the profile, and true when
+// higher values are better: throughput for STREAM profiles, 99%tile latency otherwise.
+func MeshMetric(profile string) (string, bool) {
+	if hasLatency(profile) {
+		return "usec", false
+	}
+	return "Mb/s", true
+}
+
+func meshTestID(r MeshResult) string {
+	return fmt.Sprintf("%s %s (message size %d, parallelism %d)", r.Driver, r.Profile, r.MessageSize, r.Parallelism)
+}
+
+// FlagMeshOutliers computes, for each test, the fleet median over the pairs and flags
+// the pairs which are worse than the median by more than tolerance percent.
+func FlagMeshOutliers(results []MeshResult, tolerance float64) {
+	values := make(map[string][]float64)
+	for _, r := range results {
+		if !r.Failed() {
+			values[meshTestID(r)] = append(values[meshTestID(r)], r.Value)
+		}
+	}
+	for i, r := range results {
+		if r.Failed() {
+			continue
+		}
+		median, err := stats.Median(values[meshTestID(r)])
+		if err != nil || median == 0 {
+			continue
+		}
+		_, higherIsBetter := MeshMetric(r.Profile)
+		results[i].Median = median
+		results[i].Deviation = (r.Value - median) / median * 100
+		if higherIsBetter {
+			results[i].Outlier = results[i].Deviation < -tolerance
+		} else {
+			results[i].Outlier = results[i].Deviation > tolerance
+		}
+	}
+}
+
+// ShowMeshMatrix presents a client x server matrix per test via stdout, followed by
+// the outlier and failed pairs. Outliers are marked with !, failed pairs with x.
+func ShowMeshMatrix(results []MeshResult) {
+	var tests []string
+	byTest := make(map[string][]MeshResult)
+	for _, r := range results {
+		id := meshTestID(r)
+		if _, ok := byTest[id]; !ok {
+			tests = append(tests, id)
+		}
+		byTest[id] = append(byTest[id], r)
+	}
+	for _, id := range tests {
+		rs := byTest[id]
+		nodeSet := make(map[string]bool)
+		cells := make(map[[2]string]MeshResult)
+		for _, r := range rs {
+			nodeSet[r.Client] = true
+			nodeSet[r.Server] = true
+			cells[[2]string{r.Client, r.Server}] = r
+		}
+		var nodes []string
+		for n := range nodeSet {
+			nodes = append(nodes, n)
+		}
+		sort.Strings(nodes)
+		metric, _ := MeshMetric(rs[0].Profile)
+		fmt.Printf("🕸️  %s, %s, client (rows) to server (columns)\n", id, metric)
+		table := initTable(append([]string{"Client \\ Server"}, nodes...))
+		for _, c := range nodes {
+			row := []string{c}
+			for _, s := range nodes {
+				r, ok := cells[[2]string{c, s}]
+				switch {
+				case c == s:
+					row = append(row, "-")
+				case !ok:
+					row = append(row, "")
+				case r.Failed():
+					row = append(row, "x")
+				case r.Outlier:
+					row = append(row, fmt.Sprintf("%.2f !", r.Value))
+				default:
+					row = append(row, fmt.Sprintf("%.2f", r.Value))
+				}
+			}
+			table.Append(row)
+		}
+		table.Render()
+	}
+	table := initTable([]string{"Driver", "Scenario", "Message Size", "Parallelism", "Client", "Server", "Value", "Fleet Median", "Deviation", "Reason"})
+	rows := 0
+	for _, r := range results {
+		metric, _ := MeshMetric(r.Profile)
+		if r.Failed() {
+			table.Append([]string{r.Driver, r.Profile, strconv.Itoa(r.MessageSize), strconv.Itoa(r.Parallelism), r.Client, r.Server, "", "", "", r.Reason})
+			rows++
+		} else if r.Outlier {
+			table.Append([]string{r.Driver, r.Profile, strconv.Itoa(r.MessageSize), strconv.Itoa(r.Parallelism), r.Client, r.Server, fmt.Sprintf("%.2f (%s)", r.Value, metric), fmt.Sprintf("%.2f (%s)", r.Median, metric), fmt.Sprintf("%+.1f%%", r.Deviation), ""})
+			rows++
+		}
+	}
+	if rows == 0 {
+		logging.Info("✅ No outlier or failed pair")
+		return
+	}
+	fmt.Println("⚠️  Outlier and failed pairs")
+	table.Render()
+}
@@ -0,0 +1,26 @@
+package result
+
+import "testing"
+
+func TestFlagMeshOutliers(t *testing.T) {
+	results := []MeshResult{
+		{Driver: "netperf", Profile: "TCP_STREAM", Client: "a", Server: "b", Value: 9000},
+		{Driver: "netperf", Profile: "TCP_STREAM", Client: "b", Server: "a", Value: 9100},
+		{Driver: "netperf", Profile: "TCP_STREAM", Client: "a", Server: "c", Value: 8900},
+		{Driver: "netperf", Profile: "TCP_STREAM", Client: "c", Server: "a", Value: 4000},
+		{Driver: "netperf", Profile: "TCP_STREAM", Client: "b", Server: "c", Reason: "timeout"},
+		{Driver: "netperf", Profile: "TCP_RR", Client: "a", Server: "b", Value: 50},
+		{Driver: "netperf", Profile: "TCP_RR", Client: "b", Server: "a", Value: 52},
+		{Driver: "netperf", Profile: "TCP_RR", Client: "a", Server: "c", Value: 120},
+	}
+	FlagMeshOutliers(results, 20)
+	want := []bool{false, false, false, true, false, false, false, true}
+	for i, r := range results {
+		if r.Outlier != want[i] {
+			t.Fatalf("%s %s->%s: outlier %t, want %t (median %f, deviation %f)", r.Profile, r.Client, r.Server, r.Outlier, want[i], r.Median, r.Deviation)
+		}
+	}
+	if results[0].Median != 8950 {
+		t.Fatalf("expected the TCP_STREAM median of the completed pairs to be 8950, got %f", results[0].Median)
+	}
+}
@@ -0,0 +1,171 @@
+package result
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/sample"
+)
+
+// Migration is the disruption of a live migration of the server or client VMI during a test.
+type Migration struct {
+	// VMI is the migrated VMI, server or client.
+	VMI        string `json:"vmi"`
+	SourceNode string `json:"sourceNode"`
+	TargetNode string `json:"targetNode"`
+	// Start and End of the migration, in seconds into the test.
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+	// Downtime is the longest window without throughput, in seconds.
+	Downtime float64 `json:"downtime"`
+	// LossPercent of the ping probes towards the server.
+	LossPercent float64 `json:"lossPercent"`
+	// BaselineRTT is the median RTT of the probes before the migration, MaxRTT the worst one, in ms.
+	BaselineRTT float64 `json:"baselineRtt"`
+	MaxRTT      float64 `json:"maxRtt"`
+	// LatencySpike is how long, in seconds, the probes were lost or slower than twice the
+	// baseline once the migration started.
+	LatencySpike float64 `json:"latencySpike"`
+	// Throughput is the time series of the test, in the metric of the test, RTT the time series
+	// of the probes, in ms. Lost probes are not in RTT but in Lost, in seconds into the test.
+	Throughput []sample.Point `json:"throughput"`
+	RTT        []sample.Point `json:"rtt"`
+	Lost       []float64      `json:"lost,omitempty"`
+}
+
+var (
+	interimRegex = regexp.MustCompile(`Interim result:\s+([0-9.]+)\s+\S+ over ([0-9.]+) seconds ending at ([0-9.]+)`)
+	probeRegex   = regexp.MustCompile(`^\[([0-9.]+)\] .*time=([0-9.]+) ms`)
+	lostRegex    = regexp.MustCompile(`^\[([0-9.]+)\] no answer yet`)
+	lossRegex    = regexp.MustCompile(`([0-9.]+)% packet loss`)
+)
+
+// ParseInterim returns the interim results of netperf demo mode, at the end of each interval,
+// in seconds after start, the epoch time the test started on the VMI.
+func ParseInterim(out string, start float64) []sample.Point {
+	var series []sample.Point
+	for _, m := range interimRegex.FindAllStringSubmatch(out, -1) {
+		v, _ := strconv.ParseFloat(m[1], 64)
+		end, _ := strconv.ParseFloat(m[3], 64)
+		series = append(series, sample.Point{Offset: end - start, Value: v})
+	}
+	return series
+}
+
+// ParseProbes returns the RTT of the ping -D -O probes, the time of the lost probes, in seconds
+// after start, and the loss percent ping reports.
+func ParseProbes(out string, start float64) ([]sample.Point, []float64, float64) {
+	var rtt []sample.Point
+	var lost []float64
+	loss := 0.0
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		if m := probeRegex.FindStringSubmatch(line); m != nil {
+			t, _ := strconv.ParseFloat(m[1], 64)
+			v, _ := strconv.ParseFloat(m[2], 64)
+			rtt = append(rtt, sample.Point{Offset: t - start, Value: v})
+		} else if m := lostRegex.FindStringSubmatch(line); m != nil {
+			t, _ := strconv.ParseFloat(m[1], 64)
+			lost = append(lost, t-start)
+		} else if m := lossRegex.FindStringSubmatch(line); m != nil {
+			loss, _ = strconv.ParseFloat(m[1], 64)
+		}
+	}
+	return rtt, lost, loss
+}
+
+// Analyze computes the downtime and the latency spike of the migration from its time series.
+// interval is the interval of the throughput series, probeInterval of the probes and duration
+// the duration of the test, in seconds.
+func (m *Migration) Analyze(duration, interval, probeInterval float64) {
+	m.Downtime = Downtime(m.Throughput, duration, interval)
+	var before, all []float64
+	for _, p := range m.RTT {
+		all = append(all, p.Value)
+		if p.Offset < m.Start {
+			before = append(before, p.Value)
+		}
+		m.MaxRTT = max(m.MaxRTT, p.Value)
+	}
+	if len(before) == 0 {
+		before = all
+	}
+	if len(before) == 0 {
+		return
+	}
+	sort.Float64s(before)
+	m.BaselineRTT = before[len(before)/2]
+	threshold := max(2*m.BaselineRTT, m.BaselineRTT+1)
+	first, last := -1.0, -1.0
+	spike := func(t float64) {
+		if t < m.Start {
+			return
+		}
+		if first < 0 || t < first {
+			first = t
+		}
+		last = max(last, t)
+	}
+	for _, p := range m.RTT {
+		if p.Value > threshold {
+			spike(p.Offset)
+		}
+	}
+	for _, t := range m.Lost {
+		spike(t)
+	}
+	if first >= 0 {
+		m.LatencySpike = last - first + probeInterval
+	}
+}
+
+// Downtime returns the longest window, in seconds, without throughput in the interim results
+// of a test. netperf only reports an interval once data flows again, so an interval longer
+// than twice the reporting interval was stalled for its extra length. A series which stops
+// early stalled until the end of the test.
+func Downtime(series []sample.Point, duration, interval float64) float64 {
+	if len(series) == 0 {
+		return duration
+	}
+	longest, stalled, last := 0.0, 0.0, 0.0
+	for _, p := range series {
+		span := p.Offset - last
+		last = p.Offset
+		if p.Value == 0 {
+			stalled += span
+			continue
+		}
+		if span > 2*interval {
+			stalled += span - interval
+		}
+		longest = max(longest, stalled)
+		stalled = 0
+	}
+	if tail := duration - last; tail > 2*interval {
+		stalled += tail - interval
+	}
+	return max(longest, stalled)
+}
+
+// ShowMigrationResults presents the disruption of the live migration tests via stdout.
+func ShowMigrationResults(s ScenarioResults) {
+	rows := 0
+	table := initTable([]string{"Result Type", "Driver", "Scenario", "Message Size", "Migrated VMI", "Source -> Target", "Migration", "Avg value", "Downtime", "Probe Loss", "Baseline RTT", "Max RTT", "Latency Spike"})
+	for _, r := range s.Results {
+		m := r.Migration
+		if m == nil {
+			continue
+		}
+		avg, _ := Average(r.ThroughputSummary)
+		table.Append([]string{"🚚 Migration Results", r.Driver, r.Profile, strconv.Itoa(r.MessageSize), m.VMI, m.SourceNode + " -> " + m.TargetNode,
+			fmt.Sprintf("%.1fs-%.1fs", m.Start, m.End), fmt.Sprintf("%f (%s)", avg, r.Metric), fmt.Sprintf("%.2fs", m.Downtime), fmt.Sprintf("%.2f%%", m.LossPercent),
+			fmt.Sprintf("%.3f ms", m.BaselineRTT), fmt.Sprintf("%.3f ms", m.MaxRTT), fmt.Sprintf("%.2fs", m.LatencySpike)})
+		rows++
+	}
+	if rows > 0 {
+		table.Render()
+	}
+}
@@ -0,0 +1,88 @@
+package result
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/sample"
+)
+
+func TestParseInterim(t *testing.T) {
+	out := `MIGRATED TCP STREAM TEST from 0.0.0.0 (0.0.0.0) port 0 AF_INET to 10.128.2.15 () port 42424 AF_INET : demo
+Interim result: 9412.52 10^6bits/s over 0.500 seconds ending at 1700000000.500
+Interim result:  812.04 10^6bits/s over 2.750 seconds ending at 1700000003.250
+THROUGHPUT=8123.45
+THROUGHPUT_UNITS=10^6bits/s
+`
+	got := ParseInterim(out, 1700000000)
+	if len(got) != 2 || got[0] != (sample.Point{Offset: 0.5, Value: 9412.52}) || math.Abs(got[1].Offset-3.25) > 1e-6 || got[1].Value != 812.04 {
+		t.Fatalf("ParseInterim() = %+v", got)
+	}
+}
+
+func TestParseProbes(t *testing.T) {
+	out := `PING 10.128.2.15 (10.128.2.15) 56(84) bytes of data.
+[1700000000.200000] 64 bytes from 10.128.2.15: icmp_seq=1 ttl=64 time=0.312 ms
+[1700000000.600000] no answer yet for icmp_seq=2
+[1700000000.800000] 64 bytes from 10.128.2.15: icmp_seq=3 ttl=64 time=4.51 ms
+
+--- 10.128.2.15 ping statistics ---
+3 packets transmitted, 2 received, 33.3333% packet loss, time 600ms
+`
+	rtt, lost, loss := ParseProbes(out, 1700000000)
+	if len(rtt) != 2 || rtt[1].Value != 4.51 || math.Abs(rtt[1].Offset-0.8) > 1e-6 {
+		t.Fatalf("ParseProbes() RTT = %+v", rtt)
+	}
+	if len(lost) != 1 || math.Abs(lost[0]-0.6) > 1e-6 {
+		t.Fatalf("ParseProbes() lost = %v", lost)
+	}
+	if loss != 33.3333 {
+		t.Fatalf("ParseProbes() loss = %f, want 33.3333", loss)
+	}
+}
+
+func TestDowntime(t *testing.T) {
+	var steady []sample.Point
+	for i := 1; i <= 8; i++ {
+		steady = append(steady, sample.Point{Offset: float64(i) / 2, Value: 10})
+	}
+	testCases := []struct {
+		name   string
+		series []sample.Point
+		want   float64
+	}{
+		{"steady", steady, 0},
+		{"stalled interval", []sample.Point{{Offset: 0.5, Value: 10}, {Offset: 3, Value: 2}, {Offset: 3.5, Value: 10}, {Offset: 4, Value: 10}}, 2},
+		{"zero intervals", []sample.Point{{Offset: 0.5, Value: 10}, {Offset: 1, Value: 0}, {Offset: 1.5, Value: 0}, {Offset: 2, Value: 10}, {Offset: 2.5, Value: 10}, {Offset: 3, Value: 10}, {Offset: 3.5, Value: 10}, {Offset: 4, Value: 10}}, 1},
+		{"stopped early", []sample.Point{{Offset: 0.5, Value: 10}, {Offset: 1, Value: 10}}, 2.5},
+		{"no data", nil, 4},
+	}
+	for _, tc := range testCases {
+		if got := Downtime(tc.series, 4, 0.5); math.Abs(got-tc.want) > 1e-6 {
+			t.Fatalf("%s: Downtime() = %f, want %f", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestMigrationAnalyze(t *testing.T) {
+	m := Migration{
+		Start:      2,
+		Throughput: []sample.Point{{Offset: 0.5, Value: 10}, {Offset: 1, Value: 10}, {Offset: 1.5, Value: 10}, {Offset: 2, Value: 10}},
+		RTT: []sample.Point{
+			{Offset: 0.2, Value: 0.3}, {Offset: 0.4, Value: 0.4}, {Offset: 0.6, Value: 0.5},
+			{Offset: 2.2, Value: 0.4}, {Offset: 2.4, Value: 9}, {Offset: 3.0, Value: 0.4},
+		},
+		Lost: []float64{2.6, 2.8},
+	}
+	m.Analyze(2, 0.5, 0.2)
+	if m.BaselineRTT != 0.4 || m.MaxRTT != 9 {
+		t.Fatalf("Analyze() baseline %f max %f, want 0.4 and 9", m.BaselineRTT, m.MaxRTT)
+	}
+	// From the slow probe at 2.4s to the last lost one at 2.8s, plus one probe interval.
+	if math.Abs(m.LatencySpike-0.6) > 1e-6 {
+		t.Fatalf("Analyze() latency spike %f, want 0.6", m.LatencySpike)
+	}
+	if m.Downtime != 0 {
+		t.Fatalf("Analyze() downtime %f, want 0", m.Downtime)
+	}
+}
@@ -42,8 +42,17 @@ type Data struct {
 	LatencySummary    []float64 // 99%tile latency summary
 	LossSummary       []float64
 	RetransmitSummary []float64
-	ClientMetrics     metrics.NodeCPU
-	ServerMetrics     metrics.NodeCPU
+	// Warm-up samples, excluded from the statistics
+	WarmupThroughputSummary []float64
+	WarmupLatencySummary    []float64
+	// Endpoints are the nodes of the clients of an incast, of the servers of a fan-out, or
+	// of the pairs, with the throughput of each endpoint and Jain's fairness index of the
+	// endpoints per sample. The summaries above are aggregated.
+	Endpoints                 []string
+	EndpointThroughputSummary [][]float64
+	FairnessSummary           []float64
+	ClientMetrics             metrics.NodeCPU
+	ServerMetrics             metrics.NodeCPU
 	// CPUCollected covers node CPU mode metrics; vSwitch metrics are collected independently.
 	ClientCPUCollected bool
 	ServerCPUCollected bool
@@ -58,6 +67,109 @@ type Data struct {
 	MacvlanInfo        string
 	LocalnetInfo       string
 	Virt               bool
+	// Path is the client to server path of the test, pod-to-pod, vm-to-vm, pod-to-vm or vm-to-pod.
+	Path string
+	// RuntimeClass is the runtime class of the pods of the test, empty for the default runtime.
+	RuntimeClass string
+	// VMSpec is the spec of the client VMI of the VM tests.
+	VMSpec *config.VMSpec
+	// QOSClass is the QoS class of the client pod, or of the virt-launcher pod of the client VMI.
+	QOSClass string
+	// Migration is the disruption of the live migration of a migrate test.
+	Migration *Migration
+	// Status is StatusFailed when the test did not complete, with the cause in Reason.
+	Status string
+	Reason string
+}
+
+// Status of a test
+const (
+	StatusCompleted = "completed"
+	StatusFailed    = "failed"
+)
+
+// Client to server paths of a test
+const (
+	PathPodToPod = "pod-to-pod"
+	PathVMToVM   = "vm-to-vm"
+	PathPodToVM  = "pod-to-vm"
+	PathVMToPod  = "vm-to-pod"
+)
+
+// PathLabel returns the client to server path of the test. Results archived before the mixed
+// paths only tell whether the test ran between VMs.
+func PathLabel(r Data) string {
+	switch {
+	case r.Path != "":
+		return r.Path
+	case r.Virt:
+		return PathVMToVM
+	}
+	return PathPodToPod
+}
+
+// RuntimeLabel returns the runtime class of the pods of the test, default for the default runtime.
+func RuntimeLabel(r Data) string {
+	if r.RuntimeClass == "" {
+		return "default"
+	}
+	return r.RuntimeClass
+}
+
+// TestID returns the identity of a test, used to match the same test across runs.
+func TestID(r Data) string {
+	return strings.Join([]string{
+		r.Driver,
+		r.Profile,
+		strconv.Itoa(r.Parallelism),
+		strconv.Itoa(r.MessageSize),
+		strconv.Itoa(r.Burst),
+		strconv.Itoa(r.Duration),
+		strconv.FormatBool(r.HostNetwork),
+		strconv.FormatBool(r.Service),
+		strconv.FormatBool(r.Virt),
+		PathLabel(r),
+		r.RuntimeClass,
+		strconv.FormatBool(r.SameNode),
+		strconv.FormatBool(r.AcrossAZ),
+		strconv.FormatBool(r.ExternalServer),
+		r.UdnInfo,
+		r.BridgeInfo,
+		r.SriovInfo,
+		r.MacvlanInfo,
+		r.LocalnetInfo,
+		TopologyLabel(r),
+		r.Migrate,
+		strconv.Itoa(r.MigrateAfter),
+	}, "|")
+}
+
+// Failed returns true when the test did not complete.
+func (r Data) Failed() bool {
+	return r.Status == StatusFailed
+}
+
+// Completed returns a copy of the results without the failed tests.
+func Completed(s ScenarioResults) ScenarioResults {
+	c := s
+	c.Results = nil
+	for _, r := range s.Results {
+		if !r.Failed() {
+			c.Results = append(c.Results, r)
+		}
+	}
+	return c
+}
+
+// Failures returns the number of failed tests.
+func Failures(s ScenarioResults) int {
+	n := 0
+	for _, r := range s.Results {
+		if r.Failed() {
+			n++
+		}
+	}
+	return n
 }
 
 // ScenarioResults each scenario could have multiple results
@@ -66,6 +178,9 @@ type ScenarioResults struct {
 	Virt      bool
 	Version   string
 	GitCommit string
+	// UUID and Timestamp are only set for results loaded back from a JSON result.
+	UUID      string
+	Timestamp time.Time
 	Metadata
 }
 
@@ -75,6 +190,9 @@ type Metadata struct {
 	Kernel          string `json:"kernel"`
 	OCPShortVersion string `json:"ocpShortVersion"`
 	MTU             int    `json:"mtu"`
+	// Image of the k8s-netperf pods, and its digest as resolved by the kubelet.
+	Image       string `json:"image"`
+	ImageDigest string `json:"imageDigest"`
 }
 
 // Average accepts array of floats to calculate average
@@ -114,6 +232,8 @@ func CheckHostResults(s ScenarioResults) bool {
 	return false
 }
 
+// DiffData is the throughput of the reference tests, e.g. hostNetwork, in HostPerf and of the
+// compared tests, e.g. podNetwork, in PodPerf.
 type DiffData struct {
 	MessageSize int
 	Streams     int
@@ -127,35 +247,53 @@ type Diff struct {
 	Streams     int
 }
 
-// TCPThroughputDiff accepts the Scenario Results and calculates the %diff.
+// TCPThroughputDiff accepts the Scenario Results and calculates the %diff of the podNetwork
+// tests, other than those of the sandboxed runtime class, against the hostNetwork tests.
 // returns
-func TCPThroughputDiff(s *ScenarioResults) ([]Diff, error) {
+func TCPThroughputDiff(s *ScenarioResults, sandbox string) ([]Diff, error) {
+	host := func(r Data) bool { return r.HostNetwork }
+	pod := func(r Data) bool { return !r.HostNetwork && (sandbox == "" || r.RuntimeClass != sandbox) }
+	return throughputDiff(s, host, pod), nil
+}
+
+// SandboxThroughputDiff accepts the Scenario Results and calculates the %diff of the tests
+// between the pods of the sandboxed runtime class against the tests between the other pods.
+func SandboxThroughputDiff(s *ScenarioResults, sandbox string) ([]Diff, error) {
+	pod := func(r Data) bool { return !r.HostNetwork && r.RuntimeClass != sandbox }
+	sandboxed := func(r Data) bool { return !r.HostNetwork && r.RuntimeClass == sandbox }
+	return throughputDiff(s, pod, sandboxed), nil
+}
+
+// diffable returns whether the test is a TCP_STREAM test between a client and a server pod,
+// without a service, and neither aggregated over the endpoints of a topology nor disrupted by
+// a live migration.
+func diffable(t Data) bool {
+	return t.Profile == "TCP_STREAM" && !t.Service && PathLabel(t) == PathPodToPod && t.Topology() == "" && t.Migrate == ""
+}
+
+// throughputDiff calculates the %diff of the single stream TCP_STREAM pod to pod tests of the
+// compared tests against the reference tests, for each message size.
+func throughputDiff(s *ScenarioResults, ref, cmp func(Data) bool) []Diff {
 	// We will focus on TCP STREAM
 	diffRes := []DiffData{}
 	for _, t := range s.Results {
-		if t.Profile == "TCP_STREAM" {
-			hostPerf := 0.0
-			podPerf := 0.0
-			diff := DiffData{}
-			if !t.Service && !t.Virt {
-				if t.HostNetwork {
-					hostPerf, _ = Average(t.ThroughputSummary)
-					diff.MessageSize = t.MessageSize
-					diff.HostPerf = hostPerf
-					diff.Streams = t.Parallelism
-				} else {
-					podPerf, _ = Average(t.ThroughputSummary)
-					diff.MessageSize = t.MessageSize
-					diff.PodPerf = podPerf
-					diff.Streams = t.Parallelism
-				}
-				diffRes = append(diffRes, diff)
-			}
+		if !diffable(t) {
+			continue
 		}
+		diff := DiffData{MessageSize: t.MessageSize, Streams: t.Parallelism}
+		switch {
+		case ref(t):
+			diff.HostPerf, _ = Average(t.ThroughputSummary)
+		case cmp(t):
+			diff.PodPerf, _ = Average(t.ThroughputSummary)
+		default:
+			continue
+		}
+		diffRes = append(diffRes, diff)
 	}
 	res := []Diff{}
 	for _, msg := range s.Results {
-		if !msg.Service && msg.Parallelism == 1 && msg.HostNetwork && msg.Profile == "TCP_STREAM" {
+		if diffable(msg) && msg.Parallelism == 1 && ref(msg) {
 			r := Diff{
 				Result:      doPerfDiff(&diffRes, msg.MessageSize, 1),
 				MessageSize: msg.MessageSize,
@@ -164,7 +302,7 @@ func TCPThroughputDiff(s *ScenarioResults) ([]Diff, error) {
 			res = append(res, r)
 		}
 	}
-	return res, nil
+	return res
 }
 
 func doPerfDiff(diff *[]DiffData, msg int, streams int) float64 {
@@ -180,7 +318,7 @@ func doPerfDiff(diff *[]DiffData, msg int, streams int) float64 {
 			}
 		}
 	}
-	logging.Debugf("Message Size %d : PodNetwork throughput %f, HostNetwork throughput %f for %d streams", msg, pod, host, streams)
+	logging.Debugf("Message Size %d : compared throughput %f, reference throughput %f for %d streams", msg, pod, host, streams)
 	return calDiff(host, pod)
 }
 
@@ -195,6 +333,15 @@ func initTable(header []string) *tablewriter.Table {
 	return table
 }
 
+// concat returns the columns of a table row, or header, joined together.
+func concat(parts ...[]string) []string {
+	var row []string
+	for _, p := range parts {
+		row = append(row, p...)
+	}
+	return row
+}
+
 // calDiff will determine the %diff between two values.
 // returns a float64 which is the %diff
 func calDiff(a float64, b float64) float64 {
@@ -203,13 +350,14 @@ func calDiff(a float64, b float64) float64 {
 
 // ShowPodCPU accepts ScenarioResults and presents to the user via stdout the PodCPU info
 func ShowPodCPU(s ScenarioResults) {
-	table := initTable([]string{"Result Type", "Driver", "Role", "Scenario", "Parallelism", "Host Network", "Virt mode", "Service", "External Server", "UDN Info", "Bridge Info", "SR-IOV Info", "Macvlan Info", "Localnet Info", "Message Size", "Burst", "Same node", "Pod", "Utilization"})
+	net := visibleColumns(networkColumns, s.Results)
+	table := initTable(concat([]string{"Result Type", "Driver", "Role", "Scenario", "Parallelism", "Host Network", "Virt mode", "Path", "Runtime Class", "Service", "External Server"}, columnHeaders(net), []string{"Message Size", "Burst", "Same node", "Pod", "Utilization"}))
 	for _, r := range s.Results {
 		for _, pod := range r.ClientPodCPU.Results {
-			table.Append([]string{"Pod CPU Utilization", r.Driver, "Client", r.Profile, fmt.Sprintf("%d", r.Parallelism), fmt.Sprintf("%t", r.HostNetwork), fmt.Sprintf("%t", r.Virt), fmt.Sprintf("%t", r.Service), fmt.Sprintf("%t", r.ExternalServer), r.UdnInfo, r.BridgeInfo, r.SriovInfo, r.MacvlanInfo, r.LocalnetInfo, fmt.Sprintf("%d", r.MessageSize), fmt.Sprintf("%d", r.Burst), fmt.Sprintf("%t", r.SameNode), fmt.Sprintf("%.20s", pod.Name), fmt.Sprintf("%f", pod.Value)})
+			table.Append(concat([]string{"Pod CPU Utilization", r.Driver, "Client", r.Profile, fmt.Sprintf("%d", r.Parallelism), fmt.Sprintf("%t", r.HostNetwork), fmt.Sprintf("%t", r.Virt), PathLabel(r), RuntimeLabel(r), fmt.Sprintf("%t", r.Service), fmt.Sprintf("%t", r.ExternalServer)}, columnValues(net, r), []string{fmt.Sprintf("%d", r.MessageSize), fmt.Sprintf("%d", r.Burst), fmt.Sprintf("%t", r.SameNode), fmt.Sprintf("%.20s", pod.Name), fmt.Sprintf("%f", pod.Value)}))
 		}
 		for _, pod := range r.ServerPodCPU.Results {
-			table.Append([]string{"Pod CPU Utilization", r.Driver, "Server", r.Profile, fmt.Sprintf("%d", r.Parallelism), fmt.Sprintf("%t", r.HostNetwork), fmt.Sprintf("%t", r.Virt), fmt.Sprintf("%t", r.Service), fmt.Sprintf("%t", r.ExternalServer), r.UdnInfo, r.BridgeInfo, r.SriovInfo, r.MacvlanInfo, r.LocalnetInfo, fmt.Sprintf("%d", r.MessageSize), fmt.Sprintf("%d", r.Burst), fmt.Sprintf("%t", r.SameNode), fmt.Sprintf("%.20s", pod.Name), fmt.Sprintf("%f", pod.Value)})
+			table.Append(concat([]string{"Pod CPU Utilization", r.Driver, "Server", r.Profile, fmt.Sprintf("%d", r.Parallelism), fmt.Sprintf("%t", r.HostNetwork), fmt.Sprintf("%t", r.Virt), PathLabel(r), RuntimeLabel(r), fmt.Sprintf("%t", r.Service), fmt.Sprintf("%t", r.ExternalServer)}, columnValues(net, r), []string{fmt.Sprintf("%d", r.MessageSize), fmt.Sprintf("%d", r.Burst), fmt.Sprintf("%t", r.SameNode), fmt.Sprintf("%.20s", pod.Name), fmt.Sprintf("%f", pod.Value)}))
 		}
 	}
 	table.Render()
@@ -217,13 +365,14 @@ func ShowPodCPU(s ScenarioResults) {
 
 // ShowPodMem accepts ScenarioResults and presents to the user via stdout the Podmem info
 func ShowPodMem(s ScenarioResults) {
-	table := initTable([]string{"Result Type", "Driver", "Role", "Scenario", "Parallelism", "Host Network", "Virt mode", "Service", "External Server", "UDN Info", "Bridge Info", "SR-IOV Info", "Macvlan Info", "Localnet Info", "Message Size", "Burst", "Same node", "Pod", "Utilization"})
+	net := visibleColumns(networkColumns, s.Results)
+	table := initTable(concat([]string{"Result Type", "Driver", "Role", "Scenario", "Parallelism", "Host Network", "Virt mode", "Path", "Runtime Class", "Service", "External Server"}, columnHeaders(net), []string{"Message Size", "Burst", "Same node", "Pod", "Utilization"}))
 	for _, r := range s.Results {
 		for _, pod := range r.ClientPodMem.MemResults {
-			table.Append([]string{"Pod Mem RSS Utilization", r.Driver, "Client", r.Profile, fmt.Sprintf("%d", r.Parallelism), fmt.Sprintf("%t", r.HostNetwork), fmt.Sprintf("%t", r.Virt), fmt.Sprintf("%t", r.Service), fmt.Sprintf("%t", r.ExternalServer), r.UdnInfo, r.BridgeInfo, r.SriovInfo, r.MacvlanInfo, r.LocalnetInfo, fmt.Sprintf("%d", r.MessageSize), fmt.Sprintf("%d", r.Burst), fmt.Sprintf("%t", r.SameNode), fmt.Sprintf("%.20s", pod.Name), fmt.Sprintf("%f", pod.Value)})
+			table.Append(concat([]string{"Pod Mem RSS Utilization", r.Driver, "Client", r.Profile, fmt.Sprintf("%d", r.Parallelism), fmt.Sprintf("%t", r.HostNetwork), fmt.Sprintf("%t", r.Virt), PathLabel(r), RuntimeLabel(r), fmt.Sprintf("%t", r.Service), fmt.Sprintf("%t", r.ExternalServer)}, columnValues(net, r), []string{fmt.Sprintf("%d", r.MessageSize), fmt.Sprintf("%d", r.Burst), fmt.Sprintf("%t", r.SameNode), fmt.Sprintf("%.20s", pod.Name), fmt.Sprintf("%f", pod.Value)}))
 		}
 		for _, pod := range r.ServerPodMem.MemResults {
-			table.Append([]string{"Pod Mem RSS Utilization", r.Driver, "Server", r.Profile, fmt.Sprintf("%d", r.Parallelism), fmt.Sprintf("%t", r.HostNetwork), fmt.Sprintf("%t", r.Virt), fmt.Sprintf("%t", r.Service), fmt.Sprintf("%t", r.ExternalServer), r.UdnInfo, r.BridgeInfo, r.SriovInfo, r.MacvlanInfo, r.LocalnetInfo, fmt.Sprintf("%d", r.MessageSize), fmt.Sprintf("%d", r.Burst), fmt.Sprintf("%t", r.SameNode), fmt.Sprintf("%.20s", pod.Name), fmt.Sprintf("%f", pod.Value)})
+			table.Append(concat([]string{"Pod Mem RSS Utilization", r.Driver, "Server", r.Profile, fmt.Sprintf("%d", r.Parallelism), fmt.Sprintf("%t", r.HostNetwork), fmt.Sprintf("%t", r.Virt), PathLabel(r), RuntimeLabel(r), fmt.Sprintf("%t", r.Service), fmt.Sprintf("%t", r.ExternalServer)}, columnValues(net, r), []string{fmt.Sprintf("%d", r.MessageSize), fmt.Sprintf("%d", r.Burst), fmt.Sprintf("%t", r.SameNode), fmt.Sprintf("%.20s", pod.Name), fmt.Sprintf("%f", pod.Value)}))
 		}
 	}
 	table.Render()
@@ -231,7 +380,8 @@ func ShowPodMem(s ScenarioResults) {
 
 // ShowNodeCPU accepts ScenarioResults and presents to the user via stdout the NodeCPU info
 func ShowNodeCPU(s ScenarioResults) {
-	table := initTable([]string{"Result Type", "Driver", "Role", "Scenario", "Parallelism", "Host Network", "Virt mode", "Service", "External Server", "UDN Info", "Bridge Info", "SR-IOV Info", "Macvlan Info", "Localnet Info", "Message Size", "Burst", "Same node", "Idle CPU", "User CPU", "System CPU", "Steal CPU", "IOWait CPU", "Nice CPU", "SoftIRQ CPU", "IRQ CPU"})
+	net := visibleColumns(networkColumns, s.Results)
+	table := initTable(concat([]string{"Result Type", "Driver", "Role", "Scenario", "Parallelism", "Host Network", "Virt mode", "Path", "Runtime Class", "Service", "External Server"}, columnHeaders(net), []string{"Message Size", "Burst", "Same node", "Idle CPU", "User CPU", "System CPU", "Steal CPU", "IOWait CPU", "Nice CPU", "SoftIRQ CPU", "IRQ CPU"}))
 	for _, r := range s.Results {
 		// Skip RR/CRR iperf3 Results
 		if strings.Contains(r.Profile, "RR") {
@@ -241,29 +391,44 @@ func ShowNodeCPU(s ScenarioResults) {
 		}
 		ccpu := r.ClientMetrics
 		scpu := r.ServerMetrics
-		table.Append([]string{
-			"Node CPU Utilization", r.Driver, "Client", r.Profile, fmt.Sprintf("%d", r.Parallelism), fmt.Sprintf("%t", r.HostNetwork), fmt.Sprintf("%t", r.Virt), fmt.Sprintf("%t", r.Service), fmt.Sprintf("%t", r.ExternalServer), r.UdnInfo, r.BridgeInfo, r.SriovInfo, r.MacvlanInfo, r.LocalnetInfo, fmt.Sprintf("%d", r.MessageSize), fmt.Sprintf("%d", r.Burst), fmt.Sprintf("%t", r.SameNode),
-			fmt.Sprintf("%f", ccpu.Idle), fmt.Sprintf("%f", ccpu.User), fmt.Sprintf("%f", ccpu.System), fmt.Sprintf("%f", ccpu.Steal), fmt.Sprintf("%f", ccpu.Iowait), fmt.Sprintf("%f", ccpu.Nice), fmt.Sprintf("%f", ccpu.Softirq), fmt.Sprintf("%f", ccpu.Irq),
-		})
-		table.Append([]string{
-			"Node CPU Utilization", r.Driver, "Server", r.Profile, fmt.Sprintf("%d", r.Parallelism), fmt.Sprintf("%t", r.HostNetwork), fmt.Sprintf("%t", r.Virt), fmt.Sprintf("%t", r.Service), fmt.Sprintf("%t", r.ExternalServer), r.UdnInfo, r.BridgeInfo, r.SriovInfo, r.MacvlanInfo, r.LocalnetInfo, fmt.Sprintf("%d", r.MessageSize), fmt.Sprintf("%d", r.Burst), fmt.Sprintf("%t", r.SameNode),
-			fmt.Sprintf("%f", scpu.Idle), fmt.Sprintf("%f", scpu.User), fmt.Sprintf("%f", scpu.System), fmt.Sprintf("%f", scpu.Steal), fmt.Sprintf("%f", scpu.Iowait), fmt.Sprintf("%f", scpu.Nice), fmt.Sprintf("%f", scpu.Softirq), fmt.Sprintf("%f", scpu.Irq),
-		})
+		table.Append(concat(
+			[]string{"Node CPU Utilization", r.Driver, "Client", r.Profile, fmt.Sprintf("%d", r.Parallelism), fmt.Sprintf("%t", r.HostNetwork), fmt.Sprintf("%t", r.Virt), PathLabel(r), RuntimeLabel(r), fmt.Sprintf("%t", r.Service), fmt.Sprintf("%t", r.ExternalServer)}, columnValues(net, r), []string{fmt.Sprintf("%d", r.MessageSize), fmt.Sprintf("%d", r.Burst), fmt.Sprintf("%t", r.SameNode)},
+			[]string{fmt.Sprintf("%f", ccpu.Idle), fmt.Sprintf("%f", ccpu.User), fmt.Sprintf("%f", ccpu.System), fmt.Sprintf("%f", ccpu.Steal), fmt.Sprintf("%f", ccpu.Iowait), fmt.Sprintf("%f", ccpu.Nice), fmt.Sprintf("%f", ccpu.Softirq), fmt.Sprintf("%f", ccpu.Irq)},
+		))
+		table.Append(concat(
+			[]string{"Node CPU Utilization", r.Driver, "Server", r.Profile, fmt.Sprintf("%d", r.Parallelism), fmt.Sprintf("%t", r.HostNetwork), fmt.Sprintf("%t", r.Virt), PathLabel(r), RuntimeLabel(r), fmt.Sprintf("%t", r.Service), fmt.Sprintf("%t", r.ExternalServer)}, columnValues(net, r), []string{fmt.Sprintf("%d", r.MessageSize), fmt.Sprintf("%d", r.Burst), fmt.Sprintf("%t", r.SameNode)},
+			[]string{fmt.Sprintf("%f", scpu.Idle), fmt.Sprintf("%f", scpu.User), fmt.Sprintf("%f", scpu.System), fmt.Sprintf("%f", scpu.Steal), fmt.Sprintf("%f", scpu.Iowait), fmt.Sprintf("%f", scpu.Nice), fmt.Sprintf("%f", scpu.Softirq), fmt.Sprintf("%f", scpu.Irq)},
+		))
+	}
+	table.Render()
+}
+
+// ShowFailedResults presents the tests which did not complete, and why, to the user via stdout
+func ShowFailedResults(s ScenarioResults) {
+	if Failures(s) == 0 {
+		return
+	}
+	table := initTable([]string{"Result Type", "Driver", "Scenario", "Parallelism", "Host Network", "Virt mode", "Path", "Runtime Class", "Service", "Message Size", "Burst", "Same node", "Completed Samples", "Reason"})
+	for _, r := range s.Results {
+		if r.Failed() {
+			table.Append([]string{"❌ Failed", r.Driver, r.Profile, strconv.Itoa(r.Parallelism), strconv.FormatBool(r.HostNetwork), strconv.FormatBool(r.Virt), PathLabel(r), RuntimeLabel(r), strconv.FormatBool(r.Service), strconv.Itoa(r.MessageSize), strconv.Itoa(r.Burst), strconv.FormatBool(r.SameNode), strconv.Itoa(len(r.ThroughputSummary)), r.Reason})
+		}
 	}
 	table.Render()
 }
 
 // ShowSpecificResults
 func ShowSpecificResults(s ScenarioResults) {
-	table := initTable([]string{"Type", "Driver", "Scenario", "Parallelism", "Host Network", "Virt mode", "Service", "External Server", "UDN Info", "Bridge Info", "SR-IOV Info", "Macvlan Info", "Localnet Info", "Message Size", "Burst", "Same node", "Duration", "Samples", "Avg value"})
+	net := visibleColumns(networkColumns, s.Results)
+	table := initTable(concat([]string{"Type", "Driver", "Scenario", "Parallelism", "Host Network", "Virt mode", "Path", "Runtime Class", "Service", "External Server"}, columnHeaders(net), []string{"Message Size", "Burst", "Same node", "Topology", "Duration", "Samples", "Avg value"}))
 	for _, r := range s.Results {
 		if strings.Contains(r.Profile, "TCP_STREAM") {
 			rt, _ := Average(r.RetransmitSummary)
-			table.Append([]string{"TCP Retransmissions", r.Driver, r.Profile, strconv.Itoa(r.Parallelism), strconv.FormatBool(r.HostNetwork), strconv.FormatBool(r.Virt), strconv.FormatBool(r.Service), fmt.Sprintf("%t", r.ExternalServer), r.UdnInfo, r.BridgeInfo, r.SriovInfo, r.MacvlanInfo, r.LocalnetInfo, strconv.Itoa(r.MessageSize), strconv.Itoa(r.Burst), strconv.FormatBool(r.SameNode), strconv.Itoa(r.Duration), strconv.Itoa(r.Samples), fmt.Sprintf("%f", (rt))})
+			table.Append(concat([]string{"TCP Retransmissions", r.Driver, r.Profile, strconv.Itoa(r.Parallelism), strconv.FormatBool(r.HostNetwork), strconv.FormatBool(r.Virt), PathLabel(r), RuntimeLabel(r), strconv.FormatBool(r.Service), fmt.Sprintf("%t", r.ExternalServer)}, columnValues(net, r), []string{strconv.Itoa(r.MessageSize), strconv.Itoa(r.Burst), strconv.FormatBool(r.SameNode), TopologyLabel(r), strconv.Itoa(r.Duration), strconv.Itoa(r.Samples), fmt.Sprintf("%f", (rt))}))
 		}
 		if strings.Contains(r.Profile, "UDP_STREAM") {
 			loss, _ := Average(r.LossSummary)
-			table.Append([]string{"UDP Loss Percent", r.Driver, r.Profile, strconv.Itoa(r.Parallelism), strconv.FormatBool(r.HostNetwork), strconv.FormatBool(r.Virt), strconv.FormatBool(r.Service), fmt.Sprintf("%t", r.ExternalServer), r.UdnInfo, r.BridgeInfo, r.SriovInfo, r.MacvlanInfo, r.LocalnetInfo, strconv.Itoa(r.MessageSize), strconv.Itoa(r.Burst), strconv.FormatBool(r.SameNode), strconv.Itoa(r.Duration), strconv.Itoa(r.Samples), fmt.Sprintf("%f", (loss))})
+			table.Append(concat([]string{"UDP Loss Percent", r.Driver, r.Profile, strconv.Itoa(r.Parallelism), strconv.FormatBool(r.HostNetwork), strconv.FormatBool(r.Virt), PathLabel(r), RuntimeLabel(r), strconv.FormatBool(r.Service), fmt.Sprintf("%t", r.ExternalServer)}, columnValues(net, r), []string{strconv.Itoa(r.MessageSize), strconv.Itoa(r.Burst), strconv.FormatBool(r.SameNode), TopologyLabel(r), strconv.Itoa(r.Duration), strconv.Itoa(r.Samples), fmt.Sprintf("%f", (loss))}))
 		}
 	}
 	table.Render()
@@ -271,22 +436,30 @@ func ShowSpecificResults(s ScenarioResults) {
 
 // Abstracts out the common code for results
 func renderResults(s ScenarioResults, testType string) {
-	table := initTable([]string{"Result Type", "Driver", "Scenario", "Parallelism", "Host Network", "Virt mode", "Service", "External Server", "UDN Info", "Bridge Info", "SR-IOV Info", "Macvlan Info", "Localnet Info", "Message Size", "Burst", "Same node", "Duration", "Samples", "Avg value", "95% Confidence Interval"})
+	net := visibleColumns(networkColumns, s.Results)
+	table := initTable(concat([]string{"Result Type", "Driver", "Scenario", "Parallelism", "Host Network", "Virt mode", "Path", "Runtime Class", "Service", "External Server"}, columnHeaders(net), []string{"Message Size", "Burst", "Same node", "Topology", "Duration", "Samples", "Avg value", "95% Confidence Interval", "Median value", "Min-Max", "Std Dev", "CV"}))
 	for _, r := range s.Results {
 		if strings.Contains(r.Profile, testType) {
 			if len(r.Driver) > 0 {
 				avg, _ := Average(r.ThroughputSummary)
-				var lo, hi float64
+				ci := "n/a"
 				if r.Samples > 1 {
-					_, lo, hi = ConfidenceInterval(r.ThroughputSummary, 0.95)
+					_, lo, hi := ConfidenceInterval(r.ThroughputSummary, 0.95)
+					ci = fmt.Sprintf("%f-%f (%s)", lo, hi, r.Metric)
 				}
-				table.Append([]string{fmt.Sprintf("📊 %s Results", caser.String(strings.ToLower(testType))), r.Driver, r.Profile, strconv.Itoa(r.Parallelism), strconv.FormatBool(r.HostNetwork), strconv.FormatBool(r.Virt), strconv.FormatBool(r.Service), fmt.Sprintf("%t", r.ExternalServer), r.UdnInfo, r.BridgeInfo, r.SriovInfo, r.MacvlanInfo, r.LocalnetInfo, strconv.Itoa(r.MessageSize), strconv.Itoa(r.Burst), strconv.FormatBool(r.SameNode), strconv.Itoa(r.Duration), strconv.Itoa(r.Samples), fmt.Sprintf("%f (%s)", avg, r.Metric), fmt.Sprintf("%f-%f (%s)", lo, hi, r.Metric)})
+				st := Summarize(r.ThroughputSummary)
+				table.Append(concat([]string{fmt.Sprintf("📊 %s Results", caser.String(strings.ToLower(testType))), r.Driver, r.Profile, strconv.Itoa(r.Parallelism), strconv.FormatBool(r.HostNetwork), strconv.FormatBool(r.Virt), PathLabel(r), RuntimeLabel(r), strconv.FormatBool(r.Service), fmt.Sprintf("%t", r.ExternalServer)}, columnValues(net, r), []string{strconv.Itoa(r.MessageSize), strconv.Itoa(r.Burst), strconv.FormatBool(r.SameNode), TopologyLabel(r), strconv.Itoa(r.Duration), strconv.Itoa(r.Samples), fmt.Sprintf("%f (%s)", avg, r.Metric), ci}, statsColumns(st, r.Metric)))
 			}
 		}
 	}
 	table.Render()
 }
 
+// statsColumns returns the median, min-max, standard deviation and CV columns of the samples.
+func statsColumns(st Stats, unit string) []string {
+	return []string{fmt.Sprintf("%f (%s)", st.Median, unit), fmt.Sprintf("%f-%f", st.Min, st.Max), fmt.Sprintf("%f", st.StdDev), fmt.Sprintf("%.2f%%", st.CV)}
+}
+
 // ShowStreamResult will display the throughput results
 // Currently sharing Avg value
 func ShowStreamResult(s ScenarioResults) {
@@ -307,16 +480,17 @@ func ShowRRResult(s ScenarioResults) {
 
 // ShowLatencyResult accepts NetPerfResults to display to the user via stdout
 func ShowLatencyResult(s ScenarioResults) {
+	net := visibleColumns(networkColumns, s.Results)
 
 	if checkResults(s, "STREAM_LAT") {
 		logging.Debug("Rendering TCP_STREAM_LAT Avg, P50 and P99 Latency results")
-		table := initTable([]string{"Result Type", "Driver", "Scenario", "Parallelism", "Host Network", "Virt mode", "Service", "External Server", "UDN Info", "Bridge Info", "SR-IOV Info", "Message Size", "Burst", "Same node", "Duration", "Samples", "Avg Latency", "Avg 50%tile value", "Avg 99%tile value"})
+		table := initTable(concat([]string{"Result Type", "Driver", "Scenario", "Parallelism", "Host Network", "Virt mode", "Path", "Runtime Class", "Service", "External Server"}, columnHeaders(net), []string{"Message Size", "Burst", "Same node", "Topology", "Duration", "Samples", "Avg Latency", "Avg 50%tile value", "Avg 99%tile value", "Median 99%tile value", "Min-Max", "Std Dev", "CV"}))
 		for _, r := range s.Results {
 			if strings.Contains(r.Profile, "STREAM_LAT") {
 				avg, _ := Average(r.LatencyAvgSummary)
 				p50, _ := Average(r.Latency50Summary)
 				p99, _ := Average(r.LatencySummary)
-				table.Append([]string{"Stream Latency Results", r.Driver, r.Profile, strconv.Itoa(r.Parallelism), strconv.FormatBool(r.HostNetwork), strconv.FormatBool(r.Virt), strconv.FormatBool(r.Service), fmt.Sprintf("%t", r.ExternalServer), r.UdnInfo, r.BridgeInfo, r.SriovInfo, strconv.Itoa(r.MessageSize), strconv.Itoa(r.Burst), strconv.FormatBool(r.SameNode), strconv.Itoa(r.Duration), strconv.Itoa(r.Samples), fmt.Sprintf("%f (%s)", avg, "usec"), fmt.Sprintf("%f (%s)", p50, "usec"), fmt.Sprintf("%f (%s)", p99, "usec")})
+				table.Append(concat([]string{"Stream Latency Results", r.Driver, r.Profile, strconv.Itoa(r.Parallelism), strconv.FormatBool(r.HostNetwork), strconv.FormatBool(r.Virt), PathLabel(r), RuntimeLabel(r), strconv.FormatBool(r.Service), fmt.Sprintf("%t", r.ExternalServer)}, columnValues(net, r), []string{strconv.Itoa(r.MessageSize), strconv.Itoa(r.Burst), strconv.FormatBool(r.SameNode), TopologyLabel(r), strconv.Itoa(r.Duration), strconv.Itoa(r.Samples), fmt.Sprintf("%f (%s)", avg, "usec"), fmt.Sprintf("%f (%s)", p50, "usec"), fmt.Sprintf("%f (%s)", p99, "usec")}, statsColumns(Summarize(r.LatencySummary), "usec")))
 			}
 		}
 		table.Render()
@@ -324,11 +498,11 @@ func ShowLatencyResult(s ScenarioResults) {
 
 	if checkResults(s, "RR") {
 		logging.Debug("Rendering RR P99 Latency results")
-		table := initTable([]string{"Result Type", "Driver", "Scenario", "Parallelism", "Host Network", "Virt mode", "Service", "External Server", "UDN Info", "Bridge Info", "SR-IOV Info", "Macvlan Info", "Localnet Info", "Message Size", "Burst", "Same node", "Duration", "Samples", "Avg 99%tile value"})
+		table := initTable(concat([]string{"Result Type", "Driver", "Scenario", "Parallelism", "Host Network", "Virt mode", "Path", "Runtime Class", "Service", "External Server"}, columnHeaders(net), []string{"Message Size", "Burst", "Same node", "Topology", "Duration", "Samples", "Avg 99%tile value", "Median 99%tile value", "Min-Max", "Std Dev", "CV"}))
 		for _, r := range s.Results {
 			if strings.Contains(r.Profile, "RR") {
 				p99, _ := Average(r.LatencySummary)
-				table.Append([]string{"RR Latency Results", r.Driver, r.Profile, strconv.Itoa(r.Parallelism), strconv.FormatBool(r.HostNetwork), strconv.FormatBool(r.Virt), strconv.FormatBool(r.Service), fmt.Sprintf("%t", r.ExternalServer), r.UdnInfo, r.BridgeInfo, r.SriovInfo, r.MacvlanInfo, r.LocalnetInfo, strconv.Itoa(r.MessageSize), strconv.Itoa(r.Burst), strconv.FormatBool(r.SameNode), strconv.Itoa(r.Duration), strconv.Itoa(r.Samples), fmt.Sprintf("%f (%s)", p99, "usec")})
+				table.Append(concat([]string{"RR Latency Results", r.Driver, r.Profile, strconv.Itoa(r.Parallelism), strconv.FormatBool(r.HostNetwork), strconv.FormatBool(r.Virt), PathLabel(r), RuntimeLabel(r), strconv.FormatBool(r.Service), fmt.Sprintf("%t", r.ExternalServer)}, columnValues(net, r), []string{strconv.Itoa(r.MessageSize), strconv.Itoa(r.Burst), strconv.FormatBool(r.SameNode), TopologyLabel(r), strconv.Itoa(r.Duration), strconv.Itoa(r.Samples), fmt.Sprintf("%f (%s)", p99, "usec")}, statsColumns(Summarize(r.LatencySummary), "usec")))
 			}
 		}
 		table.Render()
@@ -0,0 +1,106 @@
+package result
+
+import (
+	"testing"
+
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/config"
+)
+
+func TestCompletedSkipsFailedTests(t *testing.T) {
+	s := ScenarioResults{Version: "v1", Results: []Data{
+		{Driver: "netperf", Status: StatusCompleted, ThroughputSummary: []float64{100}},
+		{Driver: "iperf3", Status: StatusFailed, Reason: "test was unsuccessful after retry"},
+		// Results archived before the status was recorded are completed.
+		{Driver: "uperf", ThroughputSummary: []float64{90}},
+	}}
+	done := Completed(s)
+	if len(done.Results) != 2 || done.Results[0].Driver != "netperf" || done.Results[1].Driver != "uperf" {
+		t.Fatalf("Completed returned %+v, want the netperf and uperf results", done.Results)
+	}
+	if done.Version != "v1" {
+		t.Fatalf("Completed returned version %q, want v1", done.Version)
+	}
+	if len(s.Results) != 3 {
+		t.Fatalf("Completed modified the results, %d left", len(s.Results))
+	}
+	if n := Failures(s); n != 1 {
+		t.Fatalf("Failures returned %d, want 1", n)
+	}
+}
+
+func TestPathLabel(t *testing.T) {
+	testCases := []struct {
+		name string
+		r    Data
+		want string
+	}{
+		{"pods", Data{}, PathPodToPod},
+		{"archived VM test", Data{Virt: true}, PathVMToVM},
+		{"pod client, VM server", Data{Path: PathPodToVM}, PathPodToVM},
+		{"VM client, pod server", Data{Virt: true, Path: PathVMToPod}, PathVMToPod},
+	}
+	for _, tc := range testCases {
+		if got := PathLabel(tc.r); got != tc.want {
+			t.Fatalf("%s: PathLabel() = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestTestIDMigration(t *testing.T) {
+	tests := []Data{
+		{Config: config.Config{Profile: "TCP_STREAM", Duration: 60}},
+		{Config: config.Config{Profile: "TCP_STREAM", Duration: 60, Migrate: config.MigrateServer}},
+		{Config: config.Config{Profile: "TCP_STREAM", Duration: 60, Migrate: config.MigrateServer, MigrateAfter: 30}},
+		{Config: config.Config{Profile: "TCP_STREAM", Duration: 60, Migrate: config.MigrateClient, MigrateAfter: 30}},
+	}
+	seen := map[string]int{}
+	for i, r := range tests {
+		id := TestID(r)
+		if j, ok := seen[id]; ok {
+			t.Fatalf("tests %d and %d have the same identity %q", j, i, id)
+		}
+		seen[id] = i
+	}
+}
+
+func TestThroughputDiff(t *testing.T) {
+	stream := func(host bool, runtimeClass string, throughput float64) Data {
+		r := Data{HostNetwork: host, RuntimeClass: runtimeClass, ThroughputSummary: []float64{throughput}}
+		r.Profile = "TCP_STREAM"
+		r.MessageSize = 1024
+		r.Parallelism = 1
+		return r
+	}
+	s := ScenarioResults{Results: []Data{
+		stream(true, "", 1000),
+		stream(false, "", 900),
+		stream(false, "kata", 600),
+	}}
+	// The aggregated throughput of a topology must not replace the one of the single pair.
+	incast := stream(false, "", 4000)
+	incast.Incast = 4
+	fanout := stream(false, "kata", 3000)
+	fanout.Fanout = 3
+	pairs := stream(false, "", 8000)
+	pairs.Pairs = 8
+	sandboxPairs := stream(false, "kata", 8000)
+	sandboxPairs.Pairs = 8
+	s.Results = append(s.Results, incast, fanout, pairs, sandboxPairs)
+	diffs, err := TCPThroughputDiff(&s, "kata")
+	if err != nil || len(diffs) != 1 {
+		t.Fatalf("TCPThroughputDiff() = %v, %v, want one diff", diffs, err)
+	}
+	if want := calDiff(1000, 900); diffs[0].Result != want {
+		t.Fatalf("TCPThroughputDiff() = %f, want the host network against the default runtime %f", diffs[0].Result, want)
+	}
+	diffs, err = SandboxThroughputDiff(&s, "kata")
+	if err != nil || len(diffs) != 1 {
+		t.Fatalf("SandboxThroughputDiff() = %v, %v, want one diff", diffs, err)
+	}
+	if want := calDiff(900, 600); diffs[0].Result != want {
+		t.Fatalf("SandboxThroughputDiff() = %f, want the default runtime against kata %f", diffs[0].Result, want)
+	}
+	if got := RuntimeLabel(s.Results[1]); got != "default" {
+		t.Fatalf("RuntimeLabel() = %q, want default", got)
+	}
+}
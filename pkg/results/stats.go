@@ -0,0 +1,114 @@
+package result
+
+import (
+	"math"
+
+	"github.com/cloud-bulldozer/k8s-netperf/pkg/logging"
+	stats "github.com/montanaflynn/stats"
+)
+
+// outlierIQRFactor is the number of interquartile ranges a sample must fall
+// outside of the first/third quartile to be flagged as an outlier.
+const outlierIQRFactor = 1.5
+
+// minOutlierSamples is the minimum number of samples required for quartiles to be meaningful.
+const minOutlierSamples = 4
+
+// Stats describes the spread of a set of samples
+type Stats struct {
+	Min      float64 `json:"min"`
+	Max      float64 `json:"max"`
+	Mean     float64 `json:"mean"`
+	Median   float64 `json:"median"`
+	StdDev   float64 `json:"stddev"`
+	CV       float64 `json:"cv"`       // coefficient of variation, in percent
+	Outliers []int   `json:"outliers"` // index of the samples outside of the IQR fences
+}
+
+// Summarize accepts array of floats and returns their descriptive statistics.
+// StdDev and CV are left at zero when there are less than two samples.
+func Summarize(vals []float64) Stats {
+	s := Stats{}
+	if len(vals) == 0 {
+		return s
+	}
+	s.Min, _ = stats.Min(vals)
+	s.Max, _ = stats.Max(vals)
+	s.Mean, _ = stats.Mean(vals)
+	s.Median, _ = stats.Median(vals)
+	if len(vals) > 1 {
+		s.StdDev, _ = stats.StandardDeviationSample(vals)
+		if s.Mean != 0 {
+			s.CV = s.StdDev / s.Mean * 100
+		}
+	}
+	s.Outliers = Outliers(vals)
+	return s
+}
+
+// Outliers returns the index of the samples which fall outside of the
+// Q1 - 1.5*IQR and Q3 + 1.5*IQR fences.
+func Outliers(vals []float64) []int {
+	var idx []int
+	if len(vals) < minOutlierSamples {
+		return idx
+	}
+	q, err := stats.Quartile(vals)
+	if err != nil {
+		return idx
+	}
+	iqr := q.Q3 - q.Q1
+	lo := q.Q1 - outlierIQRFactor*iqr
+	hi := q.Q3 + outlierIQRFactor*iqr
+	for i, v := range vals {
+		if v < lo || v > hi {
+			idx = append(idx, i)
+		}
+	}
+	return idx
+}
+
+// Converged returns true when the half-width of the 95% confidence interval of
+// the samples is within targetPct percent of their mean.
+func Converged(vals []float64, targetPct float64) bool {
+	if len(vals) < 2 {
+		return false
+	}
+	mean, lo, hi := ConfidenceInterval(vals, 0.95)
+	if mean == 0 || math.IsNaN(lo) || math.IsNaN(hi) {
+		return false
+	}
+	return (hi-lo)/2/math.Abs(mean)*100 <= targetPct
+}
+
+// CheckVariation warns about results whose throughput or latency coefficient of
+// variation exceeds cvLimit (percent), or which have outlier samples.
+// returns the number of results which exceeded the limit.
+func CheckVariation(s ScenarioResults, cvLimit float64) int {
+	noisy := 0
+	for _, r := range s.Results {
+		if len(r.Driver) < 1 {
+			continue
+		}
+		tput := Summarize(r.ThroughputSummary)
+		exceeded := false
+		if tput.CV > cvLimit {
+			logging.Warnf("😥 %s %s (parallelism %d, message size %d, host network %t, service %t) throughput CV is %.1f percent, above the %.1f percent limit", r.Driver, r.Profile, r.Parallelism, r.MessageSize, r.HostNetwork, r.Service, tput.CV, cvLimit)
+			exceeded = true
+		}
+		if hasLatency(r.Profile) {
+			ltcy := Summarize(r.LatencySummary)
+			if ltcy.CV > cvLimit {
+				logging.Warnf("😥 %s %s (parallelism %d, message size %d, host network %t, service %t) latency CV is %.1f percent, above the %.1f percent limit", r.Driver, r.Profile, r.Parallelism, r.MessageSize, r.HostNetwork, r.Service, ltcy.CV, cvLimit)
+				exceeded = true
+			}
+		}
+		if len(tput.Outliers) > 0 {
+			logging.Warnf("%s %s (parallelism %d, message size %d) has outlier throughput samples %v", r.Driver, r.Profile, r.Parallelism, r.MessageSize, tput.Outliers)
+		}
+		if exceeded {
+			noisy++
+		}
+	}
+	return noisy
+}
@@ -0,0 +1,54 @@
+package result
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSummarize(t *testing.T) {
+	st := Summarize([]float64{10, 11, 12, 11, 12, 10, 50})
+	if st.Min != 10 || st.Max != 50 || st.Median != 11 {
+		t.Fatalf("Summarize returned min=%f max=%f median=%f, want 10, 50, 11", st.Min, st.Max, st.Median)
+	}
+	if math.Abs(st.Mean-116.0/7) > 1e-9 {
+		t.Fatalf("Summarize returned mean=%f, want %f", st.Mean, 116.0/7)
+	}
+	if st.CV <= 0 {
+		t.Fatalf("Summarize returned cv=%f, want > 0", st.CV)
+	}
+	if len(st.Outliers) != 1 || st.Outliers[0] != 6 {
+		t.Fatalf("Summarize returned outliers %v, want [6]", st.Outliers)
+	}
+}
+
+func TestSummarizeSingleSample(t *testing.T) {
+	st := Summarize([]float64{42})
+	if st.Mean != 42 || st.Median != 42 || st.Min != 42 || st.Max != 42 {
+		t.Fatalf("Summarize returned %+v, want all values 42", st)
+	}
+	if st.StdDev != 0 || st.CV != 0 || len(st.Outliers) != 0 {
+		t.Fatalf("Summarize returned %+v, want no spread for a single sample", st)
+	}
+}
+
+func TestCheckVariation(t *testing.T) {
+	s := ScenarioResults{Results: []Data{
+		{Driver: "netperf", ThroughputSummary: []float64{100, 101, 99}},
+		{Driver: "netperf", ThroughputSummary: []float64{100, 200, 50}},
+	}}
+	if n := CheckVariation(s, 10); n != 1 {
+		t.Fatalf("CheckVariation returned %d, want 1", n)
+	}
+}
+
+func TestConverged(t *testing.T) {
+	if Converged([]float64{100}, 2) {
+		t.Fatal("Converged returned true for a single sample")
+	}
+	if !Converged([]float64{100, 100.5, 99.5, 100}, 2) {
+		t.Fatal("Converged returned false for stable samples")
+	}
+	if Converged([]float64{100, 150, 60}, 2) {
+		t.Fatal("Converged returned true for noisy samples")
+	}
+}
@@ -11,3 +11,9 @@ type Sample struct {
 	Metric         string
 	Driver         string
 }
+
+// Point is one value of a time series, at Offset seconds into the test.
+type Point struct {
+	Offset float64 `json:"offset"`
+	Value  float64 `json:"value"`
+}
@@ -1,8 +1,12 @@
 package virtctl
 
 import (
-	_ "embed"
+	"embed"
 )
 
-//go:embed binaries/linux/amd64/virtctl
-var virtctlLinuxAmd64 []byte
+// binaries holds the embedded virtctl binaries, binaries/<os>/<arch>/virtctl is the default
+// binary of a platform and binaries/<os>/<arch>/<version>/virtctl the binary of a KubeVirt
+// version, see make virtctl.
+//
+//go:embed binaries
+var binaries embed.FS
@@ -1,22 +1,50 @@
 package virtctl
 
 import (
+	"context"
 	"fmt"
+	"io/fs"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
+	"regexp"
 	"runtime"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	log "github.com/cloud-bulldozer/k8s-netperf/pkg/logging"
 )
 
+// validateTimeout bounds how long virtctl version may take.
+const validateTimeout = 30 * time.Second
+
 var (
-	extractedPath string
-	extractOnce   sync.Once
-	extractErr    error
+	extractedPath  string
+	extractOnce    sync.Once
+	extractErr     error
+	clusterVersion string
+	versionRegex   = regexp.MustCompile(`GitVersion:"([^"]+)"`)
 )
 
+// candidate is a virtctl binary which may be used, embedded or from the system PATH.
+type candidate struct {
+	path     string
+	embedded bool
+	// version is the KubeVirt version the binary is embedded for, empty for the default
+	// binary of the platform and the system binary.
+	version string
+}
+
+// SetClusterVersion sets the KubeVirt version of the cluster, the virtctl binary of the same
+// minor version is preferred. It must be called before GetVirtctlPath.
+func SetClusterVersion(version string) {
+	clusterVersion = version
+}
+
 // GetVirtctlPath returns the path to virtctl binary, extracting embedded binary if needed
 func GetVirtctlPath() (string, error) {
 	extractOnce.Do(func() {
@@ -25,38 +53,177 @@ func GetVirtctlPath() (string, error) {
 	return extractedPath, extractErr
 }
 
+// getVirtctlPathInternal returns the first valid binary of the minor version of the cluster,
+// or else the first valid one, the embedded binaries first and the system binary last.
 func getVirtctlPathInternal() (string, error) {
-	// Try embedded binary first
-	if embeddedPath, err := extractEmbeddedVirtctl(); err == nil {
-		log.Debugf("Using embedded virtctl binary: %s", embeddedPath)
-		return embeddedPath, nil
+	platform := runtime.GOOS + "/" + runtime.GOARCH
+	candidates := embeddedCandidates(binaries, platform, clusterVersion)
+	if len(candidates) == 0 {
+		log.Debugf("No embedded virtctl binary for platform %s", platform)
+	}
+	if systemPath, err := exec.LookPath("virtctl"); err == nil {
+		candidates = append(candidates, candidate{path: systemPath})
+	}
+	var fallback candidate
+	fallbackPath, fallbackVersion := "", ""
+	for _, c := range candidates {
+		p := c.path
+		if c.embedded {
+			var err error
+			if p, err = extractEmbeddedVirtctl(c.path); err != nil {
+				log.Debugf("Failed to extract embedded virtctl %s: %v", c.path, err)
+				continue
+			}
+		}
+		version, err := validate(p)
+		if err != nil {
+			log.Debugf("Skipping virtctl binary %s: %v", c.path, err)
+			removeExtracted(c, p)
+			continue
+		}
+		if clusterVersion == "" || sameMinor(version, clusterVersion) {
+			if fallbackPath != "" {
+				removeExtracted(fallback, fallbackPath)
+			}
+			log.Debugf("Using virtctl binary %s (%s), %s", c.path, version, p)
+			return p, nil
+		}
+		if fallbackPath == "" {
+			fallback, fallbackPath, fallbackVersion = c, p, version
+		} else {
+			removeExtracted(c, p)
+		}
+	}
+	if fallbackPath == "" {
+		return "", fmt.Errorf("virtctl not available: no valid embedded or system binary found for platform %s", platform)
+	}
+	if skew(fallbackVersion, clusterVersion) {
+		log.Warnf("😥 virtctl %s does not match the major version of KubeVirt %s in the cluster", fallbackVersion, clusterVersion)
 	} else {
-		log.Debugf("Failed to extract embedded virtctl: %v", err)
+		log.Debugf("Using virtctl %s with KubeVirt %s", fallbackVersion, clusterVersion)
 	}
+	return fallbackPath, nil
+}
 
-	// Fallback to system PATH
-	if systemPath, err := exec.LookPath("virtctl"); err == nil {
-		log.Debugf("Using system virtctl binary: %s", systemPath)
-		return systemPath, nil
+// embeddedCandidates returns the embedded binaries of the platform, in order of preference
+// for the cluster version: the same version, the same minor version, the default binary, the
+// same major version, then the others, newest first.
+func embeddedCandidates(fsys fs.FS, platform string, cluster string) []candidate {
+	dir := path.Join("binaries", platform)
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil
 	}
+	var candidates []candidate
+	for _, e := range entries {
+		switch {
+		case !e.IsDir() && e.Name() == "virtctl":
+			candidates = append(candidates, candidate{path: path.Join(dir, "virtctl"), embedded: true})
+		case e.IsDir():
+			p := path.Join(dir, e.Name(), "virtctl")
+			if _, err := fs.Stat(fsys, p); err == nil {
+				candidates = append(candidates, candidate{path: p, embedded: true, version: e.Name()})
+			}
+		}
+	}
+	rank := func(c candidate) int {
+		switch {
+		case c.version == "":
+			return 2
+		case cluster == "":
+			return 3
+		case strings.TrimPrefix(c.version, "v") == strings.TrimPrefix(cluster, "v"):
+			return 0
+		case sameMinor(c.version, cluster):
+			return 1
+		case !skew(c.version, cluster):
+			return 3
+		}
+		return 4
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		ri, rj := rank(candidates[i]), rank(candidates[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return newer(candidates[i].version, candidates[j].version)
+	})
+	return candidates
+}
+
+// parseVersion returns the major, minor and patch numbers of a version, e.g. v1.4.0-rc.1.
+func parseVersion(version string) ([3]int, bool) {
+	var n [3]int
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		version = version[:i]
+	}
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) < 2 {
+		return n, false
+	}
+	for i, p := range parts {
+		v, err := strconv.Atoi(p)
+		if err != nil {
+			return n, false
+		}
+		n[i] = v
+	}
+	return n, true
+}
 
-	return "", fmt.Errorf("virtctl not available: neither embedded nor system binary found")
+// sameMinor returns true when both versions have the same major and minor version.
+func sameMinor(a, b string) bool {
+	va, oka := parseVersion(a)
+	vb, okb := parseVersion(b)
+	return oka && okb && va[0] == vb[0] && va[1] == vb[1]
 }
 
-func extractEmbeddedVirtctl() (string, error) {
-	var binaryData []byte
+// skew returns true when both versions are known and their major versions differ.
+func skew(a, b string) bool {
+	va, oka := parseVersion(a)
+	vb, okb := parseVersion(b)
+	return oka && okb && va[0] != vb[0]
+}
 
-	// Select platform-specific binary
-	platform := runtime.GOOS + "/" + runtime.GOARCH
-	switch platform {
-	case "linux/amd64":
-		binaryData = virtctlLinuxAmd64
-	default:
-		return "", fmt.Errorf("unsupported platform: %s (only linux/amd64 is currently embedded)", platform)
+// newer returns true when version a is newer than b, versions which can not be parsed last.
+func newer(a, b string) bool {
+	va, oka := parseVersion(a)
+	vb, okb := parseVersion(b)
+	if oka != okb {
+		return oka
 	}
+	for i := range va {
+		if va[i] != vb[i] {
+			return va[i] > vb[i]
+		}
+	}
+	return a > b
+}
 
+// validate runs virtctl version on the binary, which fails for a binary of another platform
+// or a corrupted one, and returns the client version.
+func validate(p string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), validateTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, p, "version", "--client").CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("virtctl version failed: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	m := versionRegex.FindStringSubmatch(string(out))
+	if m == nil {
+		return "", fmt.Errorf("unexpected output of virtctl version: %s", strings.TrimSpace(string(out)))
+	}
+	return m[1], nil
+}
+
+func extractEmbeddedVirtctl(name string) (string, error) {
+	binaryData, err := binaries.ReadFile(name)
+	if err != nil {
+		return "", err
+	}
 	if len(binaryData) == 0 {
-		return "", fmt.Errorf("embedded virtctl binary is empty for platform %s", platform)
+		return "", fmt.Errorf("embedded virtctl binary %s is empty", name)
 	}
 
 	// Create temporary file in system temp directory
@@ -86,10 +253,20 @@ func extractEmbeddedVirtctl() (string, error) {
 		return "", fmt.Errorf("failed to make virtctl executable: %v", err)
 	}
 
-	log.Debugf("Extracted virtctl binary to: %s", tmpFile.Name())
+	log.Debugf("Extracted virtctl binary %s to: %s", name, tmpFile.Name())
 	return tmpFile.Name(), nil
 }
 
+// removeExtracted removes the binary extracted for an embedded candidate which is not used.
+func removeExtracted(c candidate, p string) {
+	if !c.embedded || !isTemporaryPath(p) {
+		return
+	}
+	if err := os.Remove(p); err != nil {
+		log.Warnf("Error removing temp file: %v", err)
+	}
+}
+
 // CleanupExtractedBinary removes the extracted virtctl binary if it was created
 func CleanupExtractedBinary() error {
 	if extractedPath != "" && isTemporaryPath(extractedPath) {
@@ -0,0 +1,86 @@
+package virtctl
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestEmbeddedCandidates(t *testing.T) {
+	fsys := fstest.MapFS{
+		"binaries/linux/arm64/virtctl":         {Data: []byte("default")},
+		"binaries/linux/arm64/v1.3.1/virtctl":  {Data: []byte("1.3.1")},
+		"binaries/linux/arm64/v1.4.0/virtctl":  {Data: []byte("1.4.0")},
+		"binaries/linux/arm64/v1.4.2/virtctl":  {Data: []byte("1.4.2")},
+		"binaries/linux/arm64/v2.0.0/virtctl":  {Data: []byte("2.0.0")},
+		"binaries/linux/arm64/README":          {Data: []byte("not a binary")},
+		"binaries/linux/amd64/v1.4.0/virtctl":  {Data: []byte("amd64")},
+		"binaries/linux/arm64/v1.5.0/notes.md": {Data: []byte("no binary")},
+	}
+	testCases := []struct {
+		name    string
+		cluster string
+		want    []string
+	}{
+		{"unknown cluster version", "", []string{"", "v2.0.0", "v1.4.2", "v1.4.0", "v1.3.1"}},
+		{"same version", "v1.4.0", []string{"v1.4.0", "v1.4.2", "", "v1.3.1", "v2.0.0"}},
+		{"same minor version", "v1.4.1", []string{"v1.4.2", "v1.4.0", "", "v1.3.1", "v2.0.0"}},
+		{"no embedded minor version", "v1.6.0", []string{"", "v1.4.2", "v1.4.0", "v1.3.1", "v2.0.0"}},
+	}
+	for _, tc := range testCases {
+		var got []string
+		for _, c := range embeddedCandidates(fsys, "linux/arm64", tc.cluster) {
+			got = append(got, c.version)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("%s: embeddedCandidates() versions = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+	if c := embeddedCandidates(fsys, "darwin/arm64", "v1.4.0"); len(c) != 0 {
+		t.Fatalf("embeddedCandidates() = %+v for a platform without binaries", c)
+	}
+}
+
+func TestVersionSkew(t *testing.T) {
+	testCases := []struct {
+		a, b      string
+		sameMinor bool
+		skew      bool
+	}{
+		{"v1.4.0", "v1.4.2", true, false},
+		{"v1.4.0-rc.1", "1.4.0", true, false},
+		{"v1.3.1", "v1.4.0", false, false},
+		{"v1.4.0", "v2.0.0", false, true},
+		{"v1.4.0", "", false, false},
+		{"devel", "v1.4.0", false, false},
+	}
+	for _, tc := range testCases {
+		if got := sameMinor(tc.a, tc.b); got != tc.sameMinor {
+			t.Fatalf("sameMinor(%q, %q) = %t, want %t", tc.a, tc.b, got, tc.sameMinor)
+		}
+		if got := skew(tc.a, tc.b); got != tc.skew {
+			t.Fatalf("skew(%q, %q) = %t, want %t", tc.a, tc.b, got, tc.skew)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "virtctl")
+	script := "#!/bin/sh\necho 'Client Version: version.Info{GitVersion:\"v1.4.0\", GitCommit:\"abc\"}'\n"
+	if err := os.WriteFile(good, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := validate(good); err != nil || v != "v1.4.0" {
+		t.Fatalf("validate() = %q, %v, want v1.4.0", v, err)
+	}
+	bad := filepath.Join(dir, "corrupted")
+	if err := os.WriteFile(bad, []byte{0x7f, 'E', 'L', 'F', 0}, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := validate(bad); err == nil {
+		t.Fatal("validate() of a corrupted binary returned no error")
+	}
+}
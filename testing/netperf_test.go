@@ -59,3 +59,60 @@ func TestBadParseConf(t *testing.T) {
 		t.Fatal("Parsing config file should have failed but succeeded")
 	}
 }
+
+// TestAutoSamplesParseV2Conf Test for success. Ensure samples: auto is parsed with defaults
+func TestAutoSamplesParseV2Conf(t *testing.T) {
+	file := "test-auto-samples-config.yml"
+	cfg, err := config.ParseV2Conf(file)
+	if err != nil {
+		t.Fatalf("Parsing config file failed: %v", err)
+	}
+	c := cfg[0]
+	if !c.AutoSamples || c.MinSamples != 3 || c.MaxSamples != 8 || c.TargetCI != 5 || c.Convergence != config.ConvergeThroughput {
+		t.Fatalf("Unexpected adaptive sampling config: %+v", c)
+	}
+	if c.Samples != c.MinSamples {
+		t.Fatalf("Samples = %d, want minSamples %d", c.Samples, c.MinSamples)
+	}
+}
+
+// TestWarmupParseV2Conf Test for success. Ensure warmup accepts both samples and seconds
+func TestWarmupParseV2Conf(t *testing.T) {
+	file := "test-warmup-config.yml"
+	cfg, err := config.ParseV2Conf(file)
+	if err != nil {
+		t.Fatalf("Parsing config file failed: %v", err)
+	}
+	if cfg[0].WarmupDuration != 30 || cfg[0].WarmupSamples != 0 || cfg[0].Cooldown != 5 {
+		t.Fatalf("Unexpected warm-up config: %+v", cfg[0])
+	}
+	if cfg[1].WarmupSamples != 2 || cfg[1].WarmupDuration != 0 {
+		t.Fatalf("Unexpected warm-up config: %+v", cfg[1])
+	}
+}
+
+// TestSectionsParseConfig Test for success. Ensure the sections are parsed along with the tests
+func TestSectionsParseConfig(t *testing.T) {
+	file := "test-sections-config.yml"
+	f, err := config.ParseConfig(file)
+	if err != nil {
+		t.Fatalf("Parsing config file failed: %v", err)
+	}
+	if len(f.Tests) != 1 || f.Tests[0].Profile != "TCP_STREAM" {
+		t.Fatalf("Unexpected tests: %+v", f.Tests)
+	}
+	if f.Image.Name != "registry.example.com/perf/k8s-netperf:latest" || f.Resources.CPU != "2" || !f.Resources.Guaranteed {
+		t.Fatalf("Unexpected image and resources: %+v %+v", f.Image, f.Resources)
+	}
+	if len(f.Scheduling.Tolerations) != 1 || f.Scheduling.PriorityClassName != "netperf" {
+		t.Fatalf("Unexpected scheduling: %+v", f.Scheduling)
+	}
+}
+
+// TestReservedParseConfig Test for failure. A test named after a section is not dropped
+func TestReservedParseConfig(t *testing.T) {
+	file := "test-bad-reserved-config.yml"
+	if _, err := config.ParseConfig(file); err == nil {
+		t.Fatal("Expected an error for the test named image")
+	}
+}